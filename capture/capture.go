@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// Record is a captured eval request, stored as a JSON line on the capture file.
+//
+// Property:
+//   - KnowledgeBase: the name of the evaluated knowledge base (rulesheet).
+//   - Version: the evaluated version of the knowledge base.
+//   - Context: the parameters sent on the request.
+//   - Result: the features returned by the evaluation.
+//   - Timestamp: the moment the request was evaluated.
+type Record struct {
+	KnowledgeBase string                 `json:"knowledgeBase"`
+	Version       string                 `json:"version"`
+	Context       map[string]interface{} `json:"context"`
+	Result        map[string]interface{} `json:"result"`
+	Timestamp     time.Time              `json:"timestamp,omitempty"`
+}
+
+// Writer appends records as JSON lines. It's safe to be used by concurrent requests.
+type Writer struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriter creates a Writer that appends the records to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Write appends a record as a JSON line
+func (w *Writer) Write(record *Record) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.encoder.Encode(record)
+}
+
+// Default is the Writer used by the eval endpoint to capture the requests. It's nil when the capture is
+// disabled.
+var Default *Writer
+
+// Setup opens the capture file configured on `FEATWS_RULLER_CAPTURE_PATH` in append mode and sets it as
+// the Default writer. It does nothing when there is no capture file configured.
+func Setup(cfg *config.Config) error {
+	if cfg.CapturePath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(cfg.CapturePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error on open the capture file: %w", err)
+	}
+
+	log.Infof("Capturing the eval requests on '%s'", cfg.CapturePath)
+	Default = NewWriter(file)
+	return nil
+}
+
+// Read reads the records of a capture file, calling the callback for each one of them. The numbers are
+// kept as `json.Number`, like on the contexts decoded by the eval endpoint.
+func Read(r io.Reader, callback func(record *Record) error) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	for {
+		record := &Record{}
+		err := decoder.Decode(record)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error on json decode: %w", err)
+		}
+
+		err = callback(record)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// TestWriteRead checks if the written records are read back from the capture file.
+func TestWriteRead(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+
+	records := []*Record{
+		{KnowledgeBase: "mykb", Version: "latest", Context: map[string]interface{}{"mynumber": "1"}, Result: map[string]interface{}{"myboolfeat": true}},
+		{KnowledgeBase: "mykb", Version: "2", Context: map[string]interface{}{}, Result: map[string]interface{}{"myboolfeat": false}},
+	}
+
+	for _, record := range records {
+		err := writer.Write(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := []*Record{}
+	err := Read(&buf, func(record *Record) error {
+		got = append(got, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("Test Fail, we want %v, we got %v", records, got)
+	}
+}
+
+// TestReadNumbers checks if the numbers of the records are read as `json.Number`, like on the contexts
+// of the eval endpoint.
+func TestReadNumbers(t *testing.T) {
+	input := strings.NewReader(`{"knowledgeBase": "mykb", "context": {"id": 123, "amount": 12345678901234567.89}, "result": {"limit": 10.50}}`)
+
+	var got *Record
+	err := Read(input, func(record *Record) error {
+		got = record
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"id": json.Number("123"), "amount": json.Number("12345678901234567.89")}
+	if !reflect.DeepEqual(got.Context, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got.Context)
+	}
+
+	if got.Result["limit"] != json.Number("10.50") {
+		t.Errorf("Test Fail, we want %v, we got %v", json.Number("10.50"), got.Result["limit"])
+	}
+}
+
+// TestSetup checks if the capture file is opened only when it's configured.
+func TestSetup(t *testing.T) {
+	defer func() {
+		Default = nil
+	}()
+
+	cfg := &config.Config{}
+	err := Setup(cfg)
+	if err != nil || Default != nil {
+		t.Fatal("the capture must be disabled without a capture file")
+	}
+
+	cfg.CapturePath = filepath.Join(t.TempDir(), "capture.jsonl")
+	err = Setup(cfg)
+	if err != nil || Default == nil {
+		t.Fatal("the capture must be enabled with a capture file")
+	}
+
+	if _, err := os.Stat(cfg.CapturePath); err != nil {
+		t.Error("the capture file was not created")
+	}
+}
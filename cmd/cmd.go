@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Command is a subcommand of the ruller binary. It receives the arguments after the command name, the
+// standard input and the standard output, and returns an error if the command fails.
+type Command func(args []string, stdin io.Reader, stdout io.Writer) error
+
+// commands are the subcommands available on the ruller binary by name.
+var commands = map[string]Command{
+	"eval":   Eval,
+	"replay": Replay,
+	"test":   Test,
+}
+
+// Has verifies if there is a subcommand with the given name.
+func Has(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by the first argument with the remaining arguments and returns the
+// exit code of the process.
+func Run(args []string) int {
+	if len(args) == 0 || !Has(args[0]) {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "usage: ruller <command> [flags]\n\ncommands: %v\n", names)
+		return 2
+	}
+
+	err := commands[args[0]](args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// loadGRL builds a local GRL file into the default knowledge base of a new evaluation service, so the
+// evaluation is isolated from any other rulesheet.
+func loadGRL(grlPath string) (services.IEval, *ast.KnowledgeBase, error) {
+	if grlPath == "" {
+		return nil, nil, fmt.Errorf("the path of the rulesheet is required")
+	}
+
+	evalService := services.NewEval(config.GetConfig())
+	err := evalService.LoadLocalGRL(grlPath, services.DefaultKnowledgeBaseName, services.DefaultKnowledgeBaseVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on load %s: %w", grlPath, err)
+	}
+
+	return evalService, evalService.GetDefaultKnowledgeBase(), nil
+}
+
+// loadStubResolver reads a JSON file with the stubbed values of the remote loaded params by resolver
+// name and param.
+func loadStubResolver(path string) (types.StubResolver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stubs := types.StubResolver{}
+	err = json.Unmarshal(data, &stubs)
+	if err != nil {
+		return nil, fmt.Errorf("error on decode %s: %w", path, err)
+	}
+	return stubs, nil
+}
+
+// decodeContexts reads a sequence of JSON objects, like a single JSON document or JSON lines, calling
+// the callback for each one of them. The numbers are kept as `json.Number`, so the amounts don't lose
+// precision as floats.
+func decodeContexts(r io.Reader, callback func(values map[string]interface{}) error) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	for {
+		values := make(map[string]interface{})
+		err := decoder.Decode(&values)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error on json decode: %w", err)
+		}
+
+		err = callback(values)
+		if err != nil {
+			return err
+		}
+	}
+}
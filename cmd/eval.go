@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/types"
+)
+
+// Eval is the `ruller eval` command. It evaluates a local GRL file against the contexts read from a
+// JSON file or, when no file is informed, from the JSON lines of the standard input, printing the
+// features of each context as a JSON line.
+//
+//	ruller eval --grl rules.grl --context ctx.json
+//	cat contexts.jsonl | ruller eval --grl rules.grl --resolvers stubs.json
+//	ruller eval --grl rules.grl --context ctx.json --fixtures fixtures/
+func Eval(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	grlPath := flags.String("grl", "", "path of the rulesheet (.grl) to evaluate")
+	contextPath := flags.String("context", "", "path of a JSON file with the contexts; reads JSON lines from stdin when empty or '-'")
+	resolversPath := flags.String("resolvers", "", "path of a JSON file with the stubbed values of the remote loaded params by resolver")
+	fixturesPath := flags.String("fixtures", "", "directory of the fixtures of the resolvers, one JSON file named after each resolver")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *fixturesPath != "" {
+		config.GetConfig().ResolverFixturesPath = *fixturesPath
+	}
+
+	evalService, knowledgeBase, err := loadGRL(*grlPath)
+	if err != nil {
+		return err
+	}
+
+	var stubs types.StubResolver
+	if *resolversPath != "" {
+		stubs, err = loadStubResolver(*resolversPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	input := stdin
+	if *contextPath != "" && *contextPath != "-" {
+		file, err := os.Open(*contextPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	encoder := json.NewEncoder(stdout)
+
+	return decodeContexts(input, func(values map[string]interface{}) error {
+		ctx := types.NewContextFromMap(values)
+		if stubs != nil {
+			ctx.Resolver = stubs
+		}
+
+		result, err := evalService.Eval(ctx, knowledgeBase)
+		if err != nil {
+			return err
+		}
+
+		return encoder.Encode(result.GetFeatures())
+	})
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/processor"
+)
+
+// evalGRL is a rulesheet with a remote loaded param used on the command tests.
+const evalGRL = `
+	rule DefaultValues salience 10 {
+		when
+			true
+		then
+			ctx.RegistryRemoteLoaded("age", "customer");
+			Retract("DefaultValues");
+	}
+
+	rule feat_adult salience 9 {
+		when
+			true
+		then
+			result.Put("adult", ctx.GetInt("age") >= 18);
+			result.Put("small", ctx.GetInt("mynumber") < 12);
+			Retract("feat_adult");
+	}
+`
+
+// writeFile creates a file with the given content on a temporary directory of the test.
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestEvalStdin checks if each JSON line of the standard input is evaluated and printed as a JSON line.
+func TestEvalStdin(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+	resolversPath := writeFile(t, "resolvers.json", `{"customer": {"age": 21}}`)
+
+	stdin := strings.NewReader("{\"mynumber\": 1}\n{\"mynumber\": \"20\"}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath, "--resolvers", resolversPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"adult\":true,\"small\":true}\n{\"adult\":true,\"small\":false}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalContextFile checks if the context is read from a JSON file.
+func TestEvalContextFile(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+	contextPath := writeFile(t, "ctx.json", `{"mynumber": 30, "age": 10}`)
+
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath, "--context", contextPath}, strings.NewReader(""), &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"adult\":false,\"small\":false}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalWithoutGRL checks if the command fails when the rulesheet isn't informed.
+func TestEvalWithoutGRL(t *testing.T) {
+	err := Eval([]string{}, strings.NewReader(""), &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected an error without the rulesheet")
+	}
+}
+
+// TestEvalFixtures checks if the remote loaded params are answered from the fixtures directory.
+func TestEvalFixtures(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+	fixturesDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(fixturesDir, "customer.json"), []byte(`{"entries": [
+		{"match": {"mynumber": 1}, "values": {"age": 21}},
+		{"values": {"age": 15}}
+	]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.LoadConfig()
+
+	stdin := strings.NewReader("{\"mynumber\": 1}\n{\"mynumber\": 20}\n")
+	var stdout bytes.Buffer
+
+	err = Eval([]string{"--grl", grlPath, "--fixtures", fixturesDir}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"adult\":true,\"small\":true}\n{\"adult\":false,\"small\":false}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalSafeGetters checks if the safe getters can be used on the rulesheets, keeping the evaluation
+// and returning the error when a param can't be converted.
+func TestEvalSafeGetters(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_limit salience 10 {
+		when
+			true
+		then
+			result.Put("limit", ctx.GetIntOr("limit", 100) * 2);
+			result.Put("score", ctx.TryGetFloat("score"));
+			Retract("feat_limit");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"limit\": \"30\", \"score\": \"none\"}\n{}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"errors\":{\"score\":[\"the param score with the value none can't be converted to float\"]},\"limit\":60,\"score\":0}\n{\"limit\":200,\"score\":0}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalSafeGettersOnWhen checks if the conversion error of a getter on the conditions of the rules
+// is recorded once, while the conditions are evaluated again after the context changes.
+func TestEvalSafeGettersOnWhen(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_minor salience 10 {
+		when
+			ctx.GetIntOr("age", 0) < 18
+		then
+			result.Put("minor", true);
+			Changed("ctx");
+			Retract("feat_minor");
+	}
+
+	rule feat_child salience 5 {
+		when
+			ctx.GetIntOr("age", 0) < 12
+		then
+			result.Put("child", true);
+			Changed("ctx");
+			Retract("feat_child");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"age\": \"unknown\"}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"child\":true,\"errors\":{\"age\":[\"the param age with the value unknown can't be converted to int\"]},\"minor\":true}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalDates checks if the rulesheets can compare the dates of the context with the processor.
+func TestEvalDates(t *testing.T) {
+	processor.Clock = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+	defer func() {
+		processor.Clock = time.Now
+	}()
+
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_dates salience 10 {
+		when
+			true
+		then
+			result.Put("adult", processor.Age(ctx.GetDate("birth")) >= 18);
+			result.Put("newAccount", processor.DaysSince(ctx.GetDate("opened")) <= 90);
+			result.Put("businessHours", processor.Hour(processor.InTimeZone(processor.Now(), "America/Sao_Paulo")) >= 9);
+			Retract("feat_dates");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"birth\": \"2006-03-11\", \"opened\": \"2024-01-01T10:00:00Z\"}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"adult\":false,\"businessHours\":true,\"newAccount\":true}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalDecimals checks if the rulesheets compute the money amounts as decimals, returned as exact
+// numbers.
+func TestEvalDecimals(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_limit salience 10 {
+		when
+			true
+		then
+			result.Put("limit", processor.DecimalRound(processor.DecimalMul(ctx.GetDecimal("income"), "0.3"), 2, "HALF_EVEN"));
+			result.Put("exact", processor.DecimalEqual(processor.DecimalAdd(ctx.GetDecimal("a"), ctx.GetDecimal("b")), "0.3"));
+			Retract("feat_limit");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"income\": \"12345678901234.57\", \"a\": 0.1, \"b\": 0.2}\n{\"income\": 12345678901234.57, \"a\": 0.1, \"b\": 0.2}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"exact\":true,\"limit\":3703703670370.37}\n{\"exact\":true,\"limit\":3703703670370.37}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalCollectionHelpers checks if the string and collection helpers can be used on the rulesheets.
+func TestEvalCollectionHelpers(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_segment salience 10 {
+		when
+			true
+		then
+			result.Put("premium", processor.In(ctx.GetString("segment"), "gold", "black"));
+			result.Put("hasCard", processor.Contains(ctx.GetSlice("cards"), 2));
+			result.Put("total", processor.Sum(ctx.GetSlice("cards")));
+			result.Put("email", processor.Match("^[^@]+@bb\\.com\\.br$", processor.Lower(ctx.GetString("email"))));
+			Retract("feat_segment");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"segment\": \"black\", \"cards\": [\"1\", 2.0], \"email\": \"Ana@BB.com.br\"}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"email\":true,\"hasCard\":true,\"premium\":true,\"total\":3}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalRollout checks if the rollout helpers can be used on the rulesheets.
+func TestEvalRollout(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_rollout salience 10 {
+		when
+			true
+		then
+			result.Put("newCheckout", processor.InPercentage(ctx.Get("id"), "new-checkout", 100));
+			result.Put("bucket", processor.Bucket(ctx.Get("id"), "new-checkout", 100));
+			result.Put("layout", processor.Variant(ctx.GetString("id"), "layout", "classic:0", "modern:100"));
+			Retract("feat_rollout");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"id\": 12345}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"bucket\":69,\"layout\":\"modern\",\"newCheckout\":true}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalVersions checks if the rulesheets target the app versions and platforms of the user agents.
+func TestEvalVersions(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_versions salience 10 {
+		when
+			true
+		then
+			result.Put("newHome", processor.VersionInRange(ctx.GetString("appVersion"), "^5.12"));
+			result.Put("legacy", processor.VersionLess(ctx.GetString("appVersion"), "5.0"));
+			result.Put("pix", processor.Platform(ctx.GetString("userAgent")) == "android" && processor.VersionAtLeast(processor.AppVersion(ctx.GetString("userAgent"), "BBApp"), "5.9"));
+			Retract("feat_versions");
+	}
+	`)
+
+	stdin := strings.NewReader("{\"appVersion\": \"5.13.0-rc.1\", \"userAgent\": \"BBApp/5.9.1 (Android 13; SM-S911B)\"}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"legacy\":false,\"newHome\":true,\"pix\":true}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestEvalGeo checks if the rulesheets target the requests by their IP and location.
+func TestEvalGeo(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", `
+	rule feat_geo salience 10 {
+		when
+			true
+		then
+			result.Put("internal", processor.IPInCIDR(ctx.GetString("ip"), "10.0.0.0/8", "192.168.0.0/16"));
+			result.Put("nearBranch", processor.Distance(ctx.GetFloat("lat"), ctx.GetFloat("lon"), -23.5614, -46.6559) <= 5);
+			result.Put("state", processor.MunicipalityState(processor.NearestMunicipality(ctx.GetFloat("lat"), ctx.GetFloat("lon"))));
+			result.Put("region", processor.StateRegion(ctx.GetString("uf")));
+			Retract("feat_geo");
+	}
+	`)
+
+	config.GetConfig().GeoMunicipalitiesPath = writeFile(t, "municipalities.csv", "code,name,uf,latitude,longitude\n"+
+		"3550308,São Paulo,SP,-23.5329,-46.6395\n"+
+		"3304557,Rio de Janeiro,RJ,-22.9129,-43.2003\n")
+	defer config.LoadConfig()
+
+	stdin := strings.NewReader("{\"ip\": \"10.20.30.40\", \"lat\": -23.55, \"lon\": -46.63, \"uf\": \"BA\"}\n")
+	var stdout bytes.Buffer
+
+	err := Eval([]string{"--grl", grlPath}, stdin, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"internal\":true,\"nearBranch\":true,\"region\":\"Nordeste\",\"state\":\"SP\"}\n"
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
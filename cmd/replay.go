@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/bancodobrasil/featws-ruller/capture"
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/tester"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Replay is the `ruller replay` command. It re-evaluates the requests of a capture file against a local
+// GRL file or another version of their knowledge bases, loaded from the configured resource loader, and
+// reports the requests whose features differ from the captured ones, where "expected" is the captured
+// result and "got" is the replayed one.
+//
+//	ruller replay --capture capture.jsonl --grl rules.grl
+//	ruller replay --capture capture.jsonl --knowledge-base mobilepf --version 12
+func Replay(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	capturePath := flags.String("capture", "", "path of the capture file; reads from stdin when empty or '-'")
+	grlPath := flags.String("grl", "", "path of the rulesheet (.grl) to replay the requests against")
+	version := flags.String("version", "", "version of the knowledge base to replay the requests against")
+	knowledgeBaseName := flags.String("knowledge-base", "", "replays only the requests of this knowledge base")
+	resolversPath := flags.String("resolvers", "", "path of a JSON file with the stubbed values of the remote loaded params by resolver")
+	failOnDiff := flags.Bool("fail-on-diff", false, "fails when any request produces different features")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if (*grlPath == "") == (*version == "") {
+		return fmt.Errorf("one of grl or version must be informed")
+	}
+
+	var evalService services.IEval
+	var getKnowledgeBase func(record *capture.Record) (*ast.KnowledgeBase, error)
+
+	if *grlPath != "" {
+		var knowledgeBase *ast.KnowledgeBase
+		evalService, knowledgeBase, err = loadGRL(*grlPath)
+		if err != nil {
+			return err
+		}
+		getKnowledgeBase = func(record *capture.Record) (*ast.KnowledgeBase, error) {
+			return knowledgeBase, nil
+		}
+	} else {
+		evalService = services.NewEval(config.GetConfig())
+		getKnowledgeBase = func(record *capture.Record) (*ast.KnowledgeBase, error) {
+			knowledgeBase, requestError := evalService.GetKnowledgeBase(context.Background(), record.KnowledgeBase, *version)
+			if requestError != nil {
+				return nil, requestError
+			}
+			return knowledgeBase, nil
+		}
+	}
+
+	var stubs types.StubResolver
+	if *resolversPath != "" {
+		stubs, err = loadStubResolver(*resolversPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	input := stdin
+	if *capturePath != "" && *capturePath != "-" {
+		file, err := os.Open(*capturePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	replayed := 0
+	different := 0
+	failed := 0
+	features := make(map[string]int)
+
+	err = capture.Read(input, func(record *capture.Record) error {
+		if *knowledgeBaseName != "" && record.KnowledgeBase != *knowledgeBaseName {
+			return nil
+		}
+		replayed++
+
+		knowledgeBase, err := getKnowledgeBase(record)
+		if err != nil {
+			failed++
+			fmt.Fprintf(stdout, "--- ERROR: #%d %s/%s: %v\n", replayed, record.KnowledgeBase, record.Version, err)
+			return nil
+		}
+
+		ctx := types.NewContextFromMap(record.Context)
+		if stubs != nil {
+			ctx.Resolver = stubs
+		}
+
+		result, err := evalService.Eval(ctx, knowledgeBase)
+		if err != nil {
+			failed++
+			fmt.Fprintf(stdout, "--- ERROR: #%d %s/%s: %v\n", replayed, record.KnowledgeBase, record.Version, err)
+			return nil
+		}
+
+		got, err := tester.Normalize(result.GetFeatures())
+		if err != nil {
+			return err
+		}
+
+		keys := tester.DiffKeys(record.Result, got)
+		if len(keys) == 0 {
+			return nil
+		}
+
+		different++
+		for _, key := range keys {
+			features[key]++
+		}
+
+		fmt.Fprintf(stdout, "--- DIFF: #%d %s/%s\n", replayed, record.KnowledgeBase, record.Version)
+		for _, diff := range tester.Diff(record.Result, got) {
+			fmt.Fprintf(stdout, "    %s\n", diff)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "replayed %d requests: %d with different features, %d with errors\n", replayed, different, failed)
+
+	keys := make([]string, 0, len(features))
+	for key := range features {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(stdout, "    %s: %d\n", key, features[key])
+	}
+
+	if *failOnDiff && (different > 0 || failed > 0) {
+		return fmt.Errorf("%d of %d requests produced different features", different+failed, replayed)
+	}
+	return nil
+}
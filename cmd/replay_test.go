@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// replayCapture has a request that produces the same features and another one that doesn't.
+const replayCapture = `{"knowledgeBase": "mykb", "version": "1", "context": {"mynumber": 1, "age": 30}, "result": {"adult": true, "small": true}}
+{"knowledgeBase": "mykb", "version": "1", "context": {"mynumber": 20, "age": 30}, "result": {"adult": true, "small": true}}
+{"knowledgeBase": "otherkb", "version": "1", "context": {"mynumber": 20, "age": 30}, "result": {}}
+`
+
+// TestReplay checks if the requests with different features are reported.
+func TestReplay(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+	capturePath := writeFile(t, "capture.jsonl", replayCapture)
+
+	var stdout bytes.Buffer
+
+	err := Replay([]string{"--capture", capturePath, "--grl", grlPath, "--knowledge-base", "mykb"}, strings.NewReader(""), &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `--- DIFF: #2 mykb/1
+    small: expected true, got false
+replayed 2 requests: 1 with different features, 0 with errors
+    small: 1
+`
+	if stdout.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, stdout.String())
+	}
+}
+
+// TestReplayFailOnDiff checks if the command fails when asked to and some request differs.
+func TestReplayFailOnDiff(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+
+	err := Replay([]string{"--grl", grlPath, "--fail-on-diff"}, strings.NewReader(replayCapture), &bytes.Buffer{})
+	if err == nil || err.Error() != "2 of 3 requests produced different features" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestReplayWithoutTarget checks if the command fails without a rulesheet or a version to replay against.
+func TestReplayWithoutTarget(t *testing.T) {
+	err := Replay([]string{}, strings.NewReader(replayCapture), &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected an error without grl or version")
+	}
+}
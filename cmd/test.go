@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bancodobrasil/featws-ruller/tester"
+)
+
+// Test is the `ruller test` command. It runs the golden test cases of a directory against a local GRL
+// file, with the resolvers stubbed by each case, printing the diffs of the failed cases. It fails when
+// any case fails.
+//
+//	ruller test --grl rules.grl --cases cases/ --junit report.xml
+func Test(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	grlPath := flags.String("grl", "", "path of the rulesheet (.grl) to test")
+	casesDir := flags.String("cases", "", "directory with the test cases as JSON files")
+	junitPath := flags.String("junit", "", "path of the JUnit XML report to write")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *casesDir == "" {
+		return fmt.Errorf("the directory of the test cases is required")
+	}
+
+	evalService, knowledgeBase, err := loadGRL(*grlPath)
+	if err != nil {
+		return err
+	}
+
+	cases, err := tester.LoadCases(*casesDir)
+	if err != nil {
+		return err
+	}
+
+	if len(cases) == 0 {
+		return fmt.Errorf("there are no test cases on %s", *casesDir)
+	}
+
+	report := tester.Run(filepath.Base(*grlPath), evalService, knowledgeBase, cases)
+	report.WriteText(stdout)
+
+	if *junitPath != "" {
+		file, err := os.Create(*junitPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		err = report.WriteJUnit(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	if report.Failed() > 0 {
+		return fmt.Errorf("%d of %d cases failed", report.Failed(), len(report.Results))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTest checks if the cases are run and the JUnit XML report is written.
+func TestTest(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+	casesDir := filepath.Dir(writeFile(t, "adult.json", `{
+		"context": {"mynumber": 1},
+		"resolvers": {"customer": {"age": 21}},
+		"features": {"adult": true, "small": true}
+	}`))
+	junitPath := filepath.Join(t.TempDir(), "report.xml")
+
+	var stdout bytes.Buffer
+
+	err := Test([]string{"--grl", grlPath, "--cases", casesDir, "--junit", junitPath}, strings.NewReader(""), &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(stdout.String(), "--- PASS: adult") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	if _, err := os.Stat(junitPath); err != nil {
+		t.Error("the JUnit XML report was not written")
+	}
+}
+
+// TestTestFailure checks if the command fails when a case fails.
+func TestTestFailure(t *testing.T) {
+	grlPath := writeFile(t, "rules.grl", evalGRL)
+	casesDir := filepath.Dir(writeFile(t, "child.json", `{
+		"context": {"mynumber": 1},
+		"resolvers": {"customer": {"age": 21}},
+		"features": {"adult": false, "small": true}
+	}`))
+
+	var stdout bytes.Buffer
+
+	err := Test([]string{"--grl", grlPath, "--cases", casesDir}, strings.NewReader(""), &stdout)
+	if err == nil || err.Error() != "1 of 1 cases failed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "adult: expected false, got true") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -25,8 +27,51 @@ import (
 //   - ResolverBridgeURL: This property is a string that represents the URL of the resolver bridge. The resolver bridge is a service that is responsible for resolving feature flags and rules.
 //   - ResolverBridgeHeaders: This field will be used to store HTTP headers that will be sent along with requests to the resolver bridge URL. The `http.Header` type is a map of strings to slices of strings, representing the headers and their values.
 //   - ResolverBridgeHeadersStr: This property is a string representation of the HTTP headers that will be sent to the resolver bridge. It is used in conjunction with the ResolverBridgeHeaders property to set the headers for requests made to the resolver bridge. The headers can be specified as a JSON object in string format.
+//   - ResolverBridgeProtocol: The protocol of the resolver bridge, "v1" or "v2". The v2 protocol returns typed values, cache hints and the status of each param.
+//   - ResolverBridgeTransport: The transport of the resolver bridge, "http" or "grpc". With gRPC, the ResolverBridgeURL is the address of the bridge, as "host:port", "grpc://host:port" or "grpcs://host:port" to use TLS.
+//   - ResolverBridgesStr: The routes of resolvers to other resolver bridges, as a JSON array of objects with the `name` of the route, the resolver names or glob patterns to `match`, and the `url`, `headers`, `timeout`, in milliseconds, `protocol` and `transport` of the bridge. The first matching route is used and the resolvers that don't match any of them go to ResolverBridgeURL.
+//   - ResolverBridges: The routes of resolvers, parsed from ResolverBridgesStr.
+//   - ResolverContextKeysStr: The context keys sent to each resolver, as "resolver:key1|key2" pairs separated by comma. The resolvers without keys receive the whole context, except the errors.
+//   - ResolverContextKeys: The context keys by resolver name, parsed from ResolverContextKeysStr.
+//   - ResolverPrefetch: Indicates whether the remote loaded params are prefetched, calling the distinct resolvers concurrently, when the rulesheet sets the required params as configured.
+//   - ResolverPrefetchConcurrency: The maximum number of resolvers called concurrently by the prefetch.
+//   - ResolverCacheTTLStr: The TTL, in seconds, of the cached responses of each resolver, as "resolver:ttl" pairs separated by comma. Only the resolvers listed here are cached.
+//   - ResolverCacheSizeStr: The maximum number of cached responses of each resolver, as "resolver:size" pairs separated by comma.
+//   - ResolverCacheKeysStr: The context keys that identify a cached response of each resolver, as "resolver:key1|key2" pairs separated by comma. When a resolver has no keys, the whole context identifies its responses.
+//   - ResolverCacheDefaultSize: The maximum number of cached responses of the resolvers without a size on ResolverCacheSizeStr.
+//   - ResolverCache: The cache settings by resolver name, parsed from the properties above.
+//   - ResolverPluginsPath: The path of a JSON file with the in-process resolvers, constant maps and lookup tables, that are used instead of the resolver bridge.
+//   - ResolverFixturesPath: The directory of the fixtures of the resolvers, one JSON file named after each resolver. When it's set, the remote loaded params are answered from the fixtures instead of the resolver bridge.
+//   - ResolverBridgeTimeout: The timeout, in milliseconds, of each call to the resolver bridge. Zero means no timeout.
+//   - ResolverTimeoutStr: The timeout, in milliseconds, of the calls of specific resolvers, as "resolver:timeout" pairs separated by comma.
+//   - ResolverTimeout: The timeouts by resolver name, parsed from ResolverTimeoutStr.
+//   - ResolverRetries: The number of retries of a call to the resolver bridge that failed on the network or with a 5xx status.
+//   - ResolverRetryBackoff: The delay, in milliseconds, before the first retry. It doubles on each retry.
+//   - ResolverBreakerThreshold: The number of consecutive failures of a resolver that opens its circuit breaker. Zero disables the circuit breaker.
+//   - ResolverBreakerCooldown: The time, in seconds, an open circuit breaker fails fast before allowing a new call to the resolver.
+//   - TimeZone: The time zone of the dates without a zone and of the current date on the rulesheets, like "America/Sao_Paulo".
+//   - Location: The location of the time zone, loaded from TimeZone.
+//   - DateLayoutsStr: The layouts, on the Go format, of the dates informed as strings, separated by "|". They're tried before RFC3339 and "2006-01-02".
+//   - DateLayouts: The date layouts, parsed from DateLayoutsStr.
+//   - HolidaysStr: The holidays skipped by the business day functions of the rulesheets, as "2006-01-02" dates separated by comma.
+//   - Holidays: The holidays, parsed from HolidaysStr.
+//   - GeoRegionsPath: The path of a GeoJSON file with the regions of the geographic targeting, polygons named by the "name" property of their features.
+//   - GeoMunicipalitiesPath: The path of a CSV file with all the Brazilian municipalities, replacing the embedded dataset of the capitals and largest municipalities. NearestMunicipality needs it.
 //   - ExternalHost: This property represents the external host name or IP address of the server where the application is running. It is used to construct URLs for external resources and APIs.
 //   - KnowledgeBaseVersionTTL: This property is used to define the TTL of a KnowledgeBase Version when it's used a tag name version.
+//   - PlaygroundMaxCycle: The maximum number of engine cycles allowed when evaluating an uploaded rulesheet on the playground endpoint.
+//   - PlaygroundTimeout: The maximum duration, in milliseconds, of an evaluation on the playground endpoint.
+//   - ShadowKnowledgeBase: The only knowledge base evaluated with the shadow candidate version. When it's empty, all knowledge bases are shadowed.
+//   - ShadowVersion: The candidate version evaluated in the background for a sample of the served requests. The shadow evaluation is disabled when it's empty.
+//   - ShadowSampleRate: The fraction, from 0 to 1, of the served requests evaluated with the shadow candidate version.
+//   - ShadowLogSampleRate: The fraction, from 0 to 1, of the shadow mismatches that are logged.
+//   - ShadowConcurrency: The maximum number of concurrent shadow evaluations. The requests beyond it aren't shadowed.
+//   - ExposureSink: The sink of the exposures of the experiments: "log", "file" or "webhook". The exposures are disabled when it's empty.
+//   - ExposurePath: The path of the file where the exposures are appended as JSON lines by the "file" sink.
+//   - ExposureWebhookURL: The URL where the exposures are posted as JSON by the "webhook" sink.
+//   - ExposureWebhookTimeout: The timeout, in milliseconds, of each post of the "webhook" sink.
+//   - ExposureQueueSize: The maximum number of exposures waiting to be posted by the "webhook" sink. The exposures beyond it are dropped.
+//   - CapturePath: The path of the file where the eval requests and their results are appended as JSON lines, to be replayed later. The capture is disabled when it's empty.
 type Config struct {
 	ResourceLoader *ResourceLoader
 
@@ -39,12 +84,65 @@ type Config struct {
 	ResolverBridgeURL        string `mapstructure:"FEATWS_RULLER_RESOLVER_BRIDGE_URL"`
 	ResolverBridgeHeaders    http.Header
 	ResolverBridgeHeadersStr string `mapstructure:"FEATWS_RULLER_RESOLVER_BRIDGE_HEADERS"`
+	ResolverBridgeProtocol   string `mapstructure:"FEATWS_RULLER_RESOLVER_BRIDGE_PROTOCOL"`
+	ResolverBridgeTransport  string `mapstructure:"FEATWS_RULLER_RESOLVER_BRIDGE_TRANSPORT"`
+	ResolverBridgesStr       string `mapstructure:"FEATWS_RULLER_RESOLVER_BRIDGES"`
+	ResolverBridges          []*ResolverBridge
+	ResolverContextKeysStr   string `mapstructure:"FEATWS_RULLER_RESOLVER_CONTEXT_KEYS"`
+	ResolverContextKeys      map[string][]string
+
+	ResolverPrefetch            bool  `mapstructure:"FEATWS_RULLER_RESOLVER_PREFETCH"`
+	ResolverPrefetchConcurrency int64 `mapstructure:"FEATWS_RULLER_RESOLVER_PREFETCH_CONCURRENCY"`
+
+	ResolverCacheTTLStr      string `mapstructure:"FEATWS_RULLER_RESOLVER_CACHE_TTL"`
+	ResolverCacheSizeStr     string `mapstructure:"FEATWS_RULLER_RESOLVER_CACHE_SIZE"`
+	ResolverCacheKeysStr     string `mapstructure:"FEATWS_RULLER_RESOLVER_CACHE_KEYS"`
+	ResolverCacheDefaultSize int64  `mapstructure:"FEATWS_RULLER_RESOLVER_CACHE_DEFAULT_SIZE"`
+	ResolverCache            map[string]*ResolverCache
+
+	ResolverPluginsPath  string `mapstructure:"FEATWS_RULLER_RESOLVER_PLUGINS"`
+	ResolverFixturesPath string `mapstructure:"FEATWS_RULLER_RESOLVER_FIXTURES"`
+
+	ResolverBridgeTimeout    int64  `mapstructure:"FEATWS_RULLER_RESOLVER_BRIDGE_TIMEOUT"`
+	ResolverTimeoutStr       string `mapstructure:"FEATWS_RULLER_RESOLVER_TIMEOUT"`
+	ResolverTimeout          map[string]time.Duration
+	ResolverRetries          int64 `mapstructure:"FEATWS_RULLER_RESOLVER_RETRIES"`
+	ResolverRetryBackoff     int64 `mapstructure:"FEATWS_RULLER_RESOLVER_RETRY_BACKOFF"`
+	ResolverBreakerThreshold int64 `mapstructure:"FEATWS_RULLER_RESOLVER_BREAKER_THRESHOLD"`
+	ResolverBreakerCooldown  int64 `mapstructure:"FEATWS_RULLER_RESOLVER_BREAKER_COOLDOWN"`
+
+	TimeZone       string `mapstructure:"FEATWS_RULLER_TIMEZONE"`
+	Location       *time.Location
+	DateLayoutsStr string `mapstructure:"FEATWS_RULLER_DATE_LAYOUTS"`
+	DateLayouts    []string
+	HolidaysStr    string `mapstructure:"FEATWS_RULLER_HOLIDAYS"`
+	Holidays       map[string]bool
+
+	GeoRegionsPath        string `mapstructure:"FEATWS_RULLER_GEO_REGIONS"`
+	GeoMunicipalitiesPath string `mapstructure:"FEATWS_RULLER_GEO_MUNICIPALITIES"`
 
 	ExternalHost string `mapstructure:"EXTERNAL_HOST"`
 
 	KnowledgeBaseVersionTTL int64 `mapstructure:"FEATWS_RULLER_KNOWLEDGE_BASE_VERSION_TTL"`
 
 	GoroutineThreshold int64 `mapstructure:"FEATWS_RULLER_GOROUTINE_THRESHOLD"`
+
+	PlaygroundMaxCycle int64 `mapstructure:"FEATWS_RULLER_PLAYGROUND_MAX_CYCLE"`
+	PlaygroundTimeout  int64 `mapstructure:"FEATWS_RULLER_PLAYGROUND_TIMEOUT"`
+
+	CapturePath string `mapstructure:"FEATWS_RULLER_CAPTURE_PATH"`
+
+	ExposureSink           string `mapstructure:"FEATWS_RULLER_EXPOSURE_SINK"`
+	ExposurePath           string `mapstructure:"FEATWS_RULLER_EXPOSURE_PATH"`
+	ExposureWebhookURL     string `mapstructure:"FEATWS_RULLER_EXPOSURE_WEBHOOK_URL"`
+	ExposureWebhookTimeout int64  `mapstructure:"FEATWS_RULLER_EXPOSURE_WEBHOOK_TIMEOUT"`
+	ExposureQueueSize      int64  `mapstructure:"FEATWS_RULLER_EXPOSURE_QUEUE_SIZE"`
+
+	ShadowKnowledgeBase string  `mapstructure:"FEATWS_RULLER_SHADOW_KNOWLEDGE_BASE"`
+	ShadowVersion       string  `mapstructure:"FEATWS_RULLER_SHADOW_VERSION"`
+	ShadowSampleRate    float64 `mapstructure:"FEATWS_RULLER_SHADOW_SAMPLE_RATE"`
+	ShadowLogSampleRate float64 `mapstructure:"FEATWS_RULLER_SHADOW_LOG_SAMPLE_RATE"`
+	ShadowConcurrency   int64   `mapstructure:"FEATWS_RULLER_SHADOW_CONCURRENCY"`
 }
 
 // ResourceLoader represents a generic resource loader that can be either HTTP or Minio type.
@@ -71,6 +169,13 @@ type ResourceLoaderMinio struct {
 	PathTemplate string `mapstructure:"FEATWS_RULLER_RESOURCE_LOADER_MINIO_PATH_TEMPLATE"` // Path template for resources in Minio.
 }
 
+// ResolverCache represents the settings of the cache of the responses of a resolver.
+type ResolverCache struct {
+	TTL  time.Duration // Time a cached response is valid.
+	Size int           // Maximum number of cached responses.
+	Keys []string      // Context keys that identify a cached response.
+}
+
 var config = &Config{
 	ResourceLoader: &ResourceLoader{
 		HTTP:  &ResourceLoaderHTTP{},
@@ -99,12 +204,48 @@ func LoadConfig() (err error) {
 	viper.SetDefault("FEATWS_RULLER_RESOURCE_LOADER_MINIO_PATH_TEMPLATE", "{knowledgeBase}/{version}.grl")
 	viper.SetDefault("FEATWS_RULLER_RESOLVER_BRIDGE_URL", "")
 	viper.SetDefault("FEATWS_RULLER_RESOLVER_BRIDGE_HEADERS", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_BRIDGE_PROTOCOL", "v1")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_BRIDGE_TRANSPORT", "http")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_BRIDGES", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_CONTEXT_KEYS", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_PREFETCH", false)
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_PREFETCH_CONCURRENCY", "4")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_CACHE_TTL", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_CACHE_SIZE", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_CACHE_KEYS", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_CACHE_DEFAULT_SIZE", "1000")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_PLUGINS", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_FIXTURES", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_BRIDGE_TIMEOUT", "5000")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_TIMEOUT", "")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_RETRIES", "2")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_RETRY_BACKOFF", "50")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_BREAKER_THRESHOLD", "5")
+	viper.SetDefault("FEATWS_RULLER_RESOLVER_BREAKER_COOLDOWN", "30")
+	viper.SetDefault("FEATWS_RULLER_TIMEZONE", "UTC")
+	viper.SetDefault("FEATWS_RULLER_DATE_LAYOUTS", "")
+	viper.SetDefault("FEATWS_RULLER_HOLIDAYS", "")
+	viper.SetDefault("FEATWS_RULLER_GEO_REGIONS", "")
+	viper.SetDefault("FEATWS_RULLER_GEO_MUNICIPALITIES", "")
 	viper.SetDefault("FEATWS_RULLER_DEFAULT_RULES", "")
 	viper.SetDefault("PORT", "8000")
 	viper.SetDefault("FEATWS_DISABLE_SSL_VERIFY", false)
 	viper.SetDefault("EXTERNAL_HOST", "localhost:8000")
 	viper.SetDefault("FEATWS_RULLER_KNOWLEDGE_BASE_VERSION_TTL", "300")
 	viper.SetDefault("FEATWS_RULLER_GOROUTINE_THRESHOLD", "200")
+	viper.SetDefault("FEATWS_RULLER_PLAYGROUND_MAX_CYCLE", "100")
+	viper.SetDefault("FEATWS_RULLER_PLAYGROUND_TIMEOUT", "2000")
+	viper.SetDefault("FEATWS_RULLER_CAPTURE_PATH", "")
+	viper.SetDefault("FEATWS_RULLER_EXPOSURE_SINK", "")
+	viper.SetDefault("FEATWS_RULLER_EXPOSURE_PATH", "exposures.jsonl")
+	viper.SetDefault("FEATWS_RULLER_EXPOSURE_WEBHOOK_URL", "")
+	viper.SetDefault("FEATWS_RULLER_EXPOSURE_WEBHOOK_TIMEOUT", "5000")
+	viper.SetDefault("FEATWS_RULLER_EXPOSURE_QUEUE_SIZE", "1000")
+	viper.SetDefault("FEATWS_RULLER_SHADOW_KNOWLEDGE_BASE", "")
+	viper.SetDefault("FEATWS_RULLER_SHADOW_VERSION", "")
+	viper.SetDefault("FEATWS_RULLER_SHADOW_SAMPLE_RATE", "0.1")
+	viper.SetDefault("FEATWS_RULLER_SHADOW_LOG_SAMPLE_RATE", "0.01")
+	viper.SetDefault("FEATWS_RULLER_SHADOW_CONCURRENCY", "4")
 
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -153,9 +294,83 @@ func LoadConfig() (err error) {
 			config.ResolverBridgeHeaders.Set(entries[0], entries[1])
 		}
 	}
+
+	config.ResolverContextKeys = make(map[string][]string)
+	for resolver, keys := range parsePairs(config.ResolverContextKeysStr) {
+		config.ResolverContextKeys[resolver] = strings.Split(keys, "|")
+	}
+
+	config.ResolverCache = make(map[string]*ResolverCache)
+	resolverCacheSizes := parsePairs(config.ResolverCacheSizeStr)
+	resolverCacheKeys := parsePairs(config.ResolverCacheKeysStr)
+	for resolver, value := range parsePairs(config.ResolverCacheTTLStr) {
+		ttl, err := strconv.Atoi(value)
+		if err != nil || ttl <= 0 {
+			log.Warnf("Ignoring the cache of the resolver '%s' with invalid TTL: %s", resolver, value)
+			continue
+		}
+
+		cache := &ResolverCache{
+			TTL:  time.Duration(ttl) * time.Second,
+			Size: int(config.ResolverCacheDefaultSize),
+		}
+
+		if size, err := strconv.Atoi(resolverCacheSizes[resolver]); err == nil && size > 0 {
+			cache.Size = size
+		}
+
+		if keys := resolverCacheKeys[resolver]; keys != "" {
+			cache.Keys = strings.Split(keys, "|")
+		}
+
+		config.ResolverCache[resolver] = cache
+	}
+
+	config.ResolverTimeout = make(map[string]time.Duration)
+	for resolver, value := range parsePairs(config.ResolverTimeoutStr) {
+		timeout, err := strconv.Atoi(value)
+		if err != nil || timeout < 0 {
+			log.Warnf("Ignoring the invalid timeout of the resolver '%s': %s", resolver, value)
+			continue
+		}
+		config.ResolverTimeout[resolver] = time.Duration(timeout) * time.Millisecond
+	}
+
+	config.Location = parseTimeZone(config.TimeZone)
+	config.DateLayouts = parseDateLayouts(config.DateLayoutsStr)
+	config.Holidays = parseHolidays(config.HolidaysStr)
+
+	err = validateResolverProtocol(config.ResolverBridgeProtocol, config.ResolverBridgeTransport)
+	if err != nil {
+		log.Errorf("Error on Load Config: %v", err)
+		return
+	}
+
+	config.ResolverBridges, err = parseResolverBridges(config.ResolverBridgesStr, config.ResolverBridgeTimeoutDuration())
+	if err != nil {
+		log.Errorf("Error on Load Config: %v", err)
+		return
+	}
 	return
 }
 
+// ResolverBridgeTimeoutDuration returns the default timeout of the calls to the resolver bridge.
+func (c *Config) ResolverBridgeTimeoutDuration() time.Duration {
+	return time.Duration(c.ResolverBridgeTimeout) * time.Millisecond
+}
+
+// parsePairs parses a string of "key:value" pairs separated by comma into a map.
+func parsePairs(str string) map[string]string {
+	pairs := make(map[string]string)
+	for _, value := range strings.Split(str, ",") {
+		entries := strings.Split(value, ":")
+		if len(entries) == 2 {
+			pairs[strings.TrimSpace(entries[0])] = strings.TrimSpace(entries[1])
+		}
+	}
+	return pairs
+}
+
 // GetConfig returns the loaded configuration or panics if there was an error loading it.
 func GetConfig() *Config {
 	if !loaded {
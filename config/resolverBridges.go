@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// The transports of the resolver bridges.
+const (
+	ResolverTransportHTTP = "http"
+	ResolverTransportGRPC = "grpc"
+)
+
+// DefaultResolverBridgeName is the name of the route of the resolver bridge set on FEATWS_RULLER_RESOLVER_BRIDGE_URL.
+const DefaultResolverBridgeName = "default"
+
+// ResolverBridge represents a route of resolvers to a resolver bridge.
+type ResolverBridge struct {
+	Name      string        // Name of the route, used on the readiness check.
+	Match     []string      // Resolver names or glob patterns, like "cards-*", routed to this bridge.
+	URL       string        // URL of the resolver bridge.
+	Headers   http.Header   // HTTP Headers to be sent in the requests.
+	Timeout   time.Duration // Timeout of each call to the resolver bridge. Zero means no timeout.
+	Protocol  string        // Protocol of the resolver bridge: "v1" or "v2".
+	Transport string        // Transport of the resolver bridge: "http" or "grpc".
+}
+
+// GRPCTarget returns the address of a gRPC resolver bridge, whose URL can be "host:port",
+// "grpc://host:port" or, to use TLS, "grpcs://host:port".
+func (b *ResolverBridge) GRPCTarget() (address string, secure bool) {
+	if strings.HasPrefix(b.URL, "grpcs://") {
+		return strings.TrimPrefix(b.URL, "grpcs://"), true
+	}
+	return strings.TrimPrefix(b.URL, "grpc://"), false
+}
+
+// Matches verifies if a resolver is routed to the bridge by name or by glob pattern.
+func (b *ResolverBridge) Matches(resolver string) bool {
+	for _, pattern := range b.Match {
+		if matched, err := path.Match(pattern, resolver); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// resolverBridgeDefinition is the JSON definition of a route on FEATWS_RULLER_RESOLVER_BRIDGES.
+type resolverBridgeDefinition struct {
+	Name      string            `json:"name"`
+	Match     []string          `json:"match"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	Timeout   *int64            `json:"timeout"`
+	Protocol  string            `json:"protocol"`
+	Transport string            `json:"transport"`
+}
+
+// parseResolverBridges parses the JSON array of routes of FEATWS_RULLER_RESOLVER_BRIDGES. The routes
+// without timeout use the default one, and the ones without protocol and transport use the v1
+// protocol over HTTP.
+func parseResolverBridges(str string, defaultTimeout time.Duration) ([]*ResolverBridge, error) {
+	if str == "" {
+		return nil, nil
+	}
+
+	definitions := []resolverBridgeDefinition{}
+	err := json.Unmarshal([]byte(str), &definitions)
+	if err != nil {
+		return nil, fmt.Errorf("error on decode the resolver bridges: %w", err)
+	}
+
+	bridges := make([]*ResolverBridge, 0, len(definitions))
+	for i, definition := range definitions {
+		if definition.URL == "" {
+			return nil, fmt.Errorf("the resolver bridge %d hasn't an URL", i+1)
+		}
+
+		bridge := &ResolverBridge{
+			Name:      definition.Name,
+			Match:     definition.Match,
+			URL:       definition.URL,
+			Headers:   make(http.Header),
+			Timeout:   defaultTimeout,
+			Protocol:  definition.Protocol,
+			Transport: definition.Transport,
+		}
+
+		err = validateResolverProtocol(bridge.Protocol, bridge.Transport)
+		if err != nil {
+			return nil, err
+		}
+
+		if bridge.Name == "" {
+			bridge.Name = fmt.Sprintf("bridge-%d", i+1)
+		}
+
+		for key, value := range definition.Headers {
+			bridge.Headers.Set(key, value)
+		}
+
+		if definition.Timeout != nil {
+			bridge.Timeout = time.Duration(*definition.Timeout) * time.Millisecond
+		}
+
+		bridges = append(bridges, bridge)
+	}
+	return bridges, nil
+}
+
+// DefaultResolverBridge returns the route of the resolver bridge set on FEATWS_RULLER_RESOLVER_BRIDGE_URL,
+// used by the resolvers that don't match any other route.
+func (c *Config) DefaultResolverBridge() *ResolverBridge {
+	return &ResolverBridge{
+		Name:      DefaultResolverBridgeName,
+		URL:       c.ResolverBridgeURL,
+		Headers:   c.ResolverBridgeHeaders,
+		Timeout:   c.ResolverBridgeTimeoutDuration(),
+		Protocol:  c.ResolverBridgeProtocol,
+		Transport: c.ResolverBridgeTransport,
+	}
+}
+
+// validateResolverProtocol verifies if the protocol and the transport of a resolver bridge are
+// supported. An empty protocol means the v1 protocol and an empty transport means HTTP. The gRPC
+// transport only supports the v1 protocol.
+func validateResolverProtocol(protocol string, transport string) error {
+	switch protocol {
+	case "", "v1", "v2":
+	default:
+		return fmt.Errorf("unknown resolver bridge protocol: %s", protocol)
+	}
+
+	switch transport {
+	case "", ResolverTransportHTTP:
+	case ResolverTransportGRPC:
+		if protocol == "v2" {
+			return fmt.Errorf("the resolver bridge protocol v2 isn't supported on gRPC")
+		}
+	default:
+		return fmt.Errorf("unknown resolver bridge transport: %s", transport)
+	}
+	return nil
+}
+
+// ResolverBridgeFor returns the route of a resolver: the first of ResolverBridges matching it or the
+// default one. The timeout of the resolver on ResolverTimeout overrides the one of the route.
+func (c *Config) ResolverBridgeFor(resolver string) *ResolverBridge {
+	bridge := c.DefaultResolverBridge()
+	for _, candidate := range c.ResolverBridges {
+		if candidate.Matches(resolver) {
+			bridge = candidate
+			break
+		}
+	}
+
+	if timeout, ok := c.ResolverTimeout[resolver]; ok {
+		routed := *bridge
+		routed.Timeout = timeout
+		bridge = &routed
+	}
+	return bridge
+}
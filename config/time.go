@@ -0,0 +1,61 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	// the time zones are embedded, so they can be loaded on images without the tz database
+	_ "time/tzdata"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// parseTimeZone loads the location of the time zone, or UTC when it's empty or invalid.
+func parseTimeZone(zone string) *time.Location {
+	if zone == "" {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		log.Warnf("Ignoring the invalid time zone '%s', using UTC: %v", zone, err)
+		return time.UTC
+	}
+	return location
+}
+
+// parseDateLayouts parses the date layouts separated by "|".
+func parseDateLayouts(str string) []string {
+	layouts := []string{}
+	for _, layout := range strings.Split(str, "|") {
+		if layout = strings.TrimSpace(layout); layout != "" {
+			layouts = append(layouts, layout)
+		}
+	}
+	return layouts
+}
+
+// parseHolidays parses the holidays, as "2006-01-02" dates separated by comma.
+func parseHolidays(str string) map[string]bool {
+	holidays := make(map[string]bool)
+	for _, value := range strings.Split(str, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			log.Warnf("Ignoring the invalid holiday '%s'", value)
+			continue
+		}
+		holidays[value] = true
+	}
+	return holidays
+}
+
+// TimeLocation returns the location of the configured time zone, used on the dates without a zone.
+func (c *Config) TimeLocation() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
@@ -53,8 +54,11 @@ func newHandler() healthcheck.Handler {
 	}
 
 	if cfg.ResolverBridgeURL != "" {
-		resolverBridgeURL := cfg.ResolverBridgeURL
-		health.AddReadinessCheck("resolver-bridge", Get(resolverBridgeURL, 1*time.Second))
+		health.AddReadinessCheck("resolver-bridge", resolverBridgeCheck(cfg.DefaultResolverBridge()))
+	}
+
+	for _, bridge := range cfg.ResolverBridges {
+		health.AddReadinessCheck("resolver-bridge-"+bridge.Name, resolverBridgeCheck(bridge))
 	}
 
 	return health
@@ -83,6 +87,28 @@ func Get(url string, timeout time.Duration) checks.Check {
 	}
 }
 
+// resolverBridgeCheck returns the check of a resolver bridge: a GET on its URL or, for the gRPC
+// bridges, a connection to its address.
+func resolverBridgeCheck(bridge *config.ResolverBridge) checks.Check {
+	if bridge.Transport == config.ResolverTransportGRPC {
+		address, _ := bridge.GRPCTarget()
+		return Dial(address, 1*time.Second)
+	}
+	return Get(bridge.URL, 1*time.Second)
+}
+
+// Dial returns a check function that opens a TCP connection to an address with a timeout and returns
+// an error if it fails.
+func Dial(address string, timeout time.Duration) checks.Check {
+	return func() error {
+		conn, err := net.DialTimeout("tcp", address, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
 // HealthLiveHandler is a Gin HTTP handler function that wraps the LiveEndpoint
 // method of the health instance of the HealthController struct. The LiveEndpoint
 // method is a handler function that returns a 200 status code if the application is live.
@@ -2,16 +2,25 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/bancodobrasil/featws-ruller/capture"
+	"github.com/bancodobrasil/featws-ruller/experiment"
 	payloads "github.com/bancodobrasil/featws-ruller/payloads/v1"
 	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/shadow"
 	"github.com/bancodobrasil/featws-ruller/types"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
 
+// experimentsHeader is the response header with the variants of the experiments assigned on the
+// evaluation, by experiment, as a JSON object. It keeps the assignments apart from the features.
+const experimentsHeader = "X-Experiments"
+
 // EvalHandler godoc
 // @Summary 		Evaluate the rulesheet / Avaliação da folha de Regra
 // @Description     Para a realiza os testes basta clicar em *Try it out*, complete a folha de regra com os dados desejados e os demais campos caso necessário, em seguida, clique em *Execute*.
@@ -109,9 +118,12 @@ import (
 // @Param			knowledgeBase path string false "knowledgeBase"
 // @Param 			version path string false "version"
 // @Param  			parameters body payloads.Eval true "Parameters"
+// @Param			Cache-Control header string false "no-cache to bypass the cache of the resolver responses"
 // @Success 		200 {string} string "ok"
+// @Header 			200 {string} X-Experiments "the variants of the experiments assigned on the evaluation, by experiment, as a JSON object"
 // @Failure 		400,404 {object} string
 // @Failure 		500 {object} string
+// @Failure 		502 {object} string
 // @Failure 		default {object} string
 // @Security 		Authentication Api Key
 // @Router 			/eval/{knowledgeBase}/{version} [post]
@@ -140,6 +152,7 @@ func EvalHandler() gin.HandlerFunc {
 		}
 
 		decoder := json.NewDecoder(c.Request.Body)
+		decoder.UseNumber()
 		var t payloads.Eval
 		err := decoder.Decode(&t)
 		if err != nil {
@@ -152,8 +165,12 @@ func EvalHandler() gin.HandlerFunc {
 
 		ctx := types.NewContextFromMap(t)
 		ctx.RawContext = c.Request.Context()
+		ctx.BypassResolverCache = c.GetHeader("Cache-Control") == "no-cache"
 
 		result, err := services.EvalService.Eval(ctx, knowledgeBase)
+		if respondRemoteLoadError(c, err) {
+			return
+		}
 		if err != nil {
 
 			log.Errorf("Error on eval: %v", err)
@@ -165,13 +182,52 @@ func EvalHandler() gin.HandlerFunc {
 		log.Trace("Context:\n\t", ctx.GetEntries(), "\n\n")
 		log.Trace("Features:\n\t", result.GetFeatures(), "\n\n")
 
-		responseCode := http.StatusOK
+		if capture.Default != nil {
+			err = capture.Default.Write(&capture.Record{
+				KnowledgeBase: knowledgeBaseName,
+				Version:       version,
+				Context:       t,
+				Result:        result.GetFeatures(),
+				Timestamp:     time.Now(),
+			})
+			if err != nil {
+				log.Errorf("Error on capture: %v", err)
+			}
+		}
 
-		if result.Has("requiredParamErrors") {
-			responseCode = http.StatusBadRequest
+		if shadow.Default != nil {
+			shadow.Default.Submit(knowledgeBaseName, version, ctx.GetEntries(), result.GetFeatures())
 		}
 
-		c.JSON(responseCode, result.GetFeatures())
+		experiment.Emit(experiment.Default, knowledgeBaseName, version, result)
+		if experiments := result.Experiments(); len(experiments) > 0 {
+			data, _ := json.Marshal(experiments)
+			c.Header(experimentsHeader, string(data))
+		}
+
+		c.JSON(responseStatus(result), result.GetFeatures())
 	}
 
 }
+
+// respondRemoteLoadError responds a 502 Bad Gateway when the evaluation failed to load a remote param,
+// returning whether the error was responded. It's shared by the endpoints that evaluate rulesheets.
+func respondRemoteLoadError(c *gin.Context, err error) bool {
+	var remoteLoadError *types.RemoteLoadError
+	if !errors.As(err, &remoteLoadError) {
+		return false
+	}
+
+	log.Errorf("Error on eval: %v", err)
+	c.String(http.StatusBadGateway, fmt.Sprintf("Error on load remote param: %v", err))
+	return true
+}
+
+// responseStatus returns the status of the response of an evaluation, that is a 400 Bad Request when
+// required params are missing.
+func responseStatus(result *types.Result) int {
+	if result.Has("requiredParamErrors") {
+		return http.StatusBadRequest
+	}
+	return http.StatusOK
+}
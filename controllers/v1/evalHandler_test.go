@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,7 +12,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bancodobrasil/featws-ruller/capture"
 	"github.com/bancodobrasil/featws-ruller/common/errors"
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/experiment"
 	"github.com/bancodobrasil/featws-ruller/services"
 	"github.com/bancodobrasil/featws-ruller/types"
 	"github.com/gin-gonic/gin"
@@ -394,3 +399,223 @@ func TestEvalHandlerWithDefaultKnowledgeBaseEvalError(t *testing.T) {
 	}
 
 }
+
+// This is a test function that checks if the EvalHandler appends the request and its result to the
+// capture file when the capture is enabled.
+func TestEvalHandlerCapture(t *testing.T) {
+	var buf bytes.Buffer
+	capture.Default = capture.NewWriter(&buf)
+	defer func() {
+		capture.Default = nil
+	}()
+
+	services.EvalService = EvalServiceTestEvalHandlerWithDefaultKnowledgeBase{
+		t:  t,
+		kl: ast.NewKnowledgeLibrary(),
+	}
+
+	c, r := mockGin()
+
+	stringReader := strings.NewReader(`{"mynumber": "1"}`)
+	c.Request.Body = io.NopCloser(stringReader)
+
+	EvalHandler()(c)
+
+	if r.Code != http.StatusOK {
+		t.Error("got error on request evalHandler func")
+	}
+
+	var record capture.Record
+	err := json.Unmarshal(buf.Bytes(), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.KnowledgeBase != services.DefaultKnowledgeBaseName || record.Version != services.DefaultKnowledgeBaseVersion || record.Context["mynumber"] != "1" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+// EvalServiceTestEvalHandlerRemoteLoadFailure is a struct that evaluates with the real eval service a
+// knowledge base built for the test.
+//
+// Property:
+//   - IEval: the real eval service.
+//   - kb: the knowledge base returned for any name and version.
+type EvalServiceTestEvalHandlerRemoteLoadFailure struct {
+	services.IEval
+	kb *ast.KnowledgeBase
+}
+
+// GetKnowledgeBase returns the knowledge base built for the test.
+func (s EvalServiceTestEvalHandlerRemoteLoadFailure) GetKnowledgeBase(ctx context.Context, knowledgeBaseName string, version string) (*ast.KnowledgeBase, *errors.RequestError) {
+	return s.kb, nil
+}
+
+// MockHTTPClientFailing is a mock of the resolver bridge that fails on every call.
+type MockHTTPClientFailing struct {
+	http.Client
+}
+
+// Do always returns an error
+func (m *MockHTTPClientFailing) Do(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("mock do error")
+}
+
+// This is a test function that checks if the EvalHandler applies the failure policies of the remote
+// loaded params when the resolver bridge fails.
+func TestEvalHandlerRemoteLoadFailure(t *testing.T) {
+	config.GetConfig().ResolverRetries = 0
+	client := types.Client
+	types.Client = &MockHTTPClientFailing{}
+	defer func() {
+		config.LoadConfig()
+		types.Client = client
+	}()
+
+	for _, tc := range []struct {
+		policy string
+		code   int
+	}{
+		{"fallback", http.StatusOK},
+		{"required", http.StatusBadRequest},
+		{"fail", http.StatusBadGateway},
+	} {
+		evalService := services.NewEval(config.GetConfig())
+		kb, err := evalService.BuildTemporaryKnowledgeBase(fmt.Sprintf(`
+			rule DefaultValues salience 10 {
+				when
+					true
+				then
+					ctx.RegistryRemoteLoadedWithPolicy("age", "customer", "", "%s", 18);
+					Retract("DefaultValues");
+			}
+
+			rule feat_adult salience 9 {
+				when
+					true
+				then
+					result.Put("adult", ctx.GetInt("age") >= 18);
+					Retract("feat_adult");
+			}
+		`, tc.policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		services.EvalService = EvalServiceTestEvalHandlerRemoteLoadFailure{IEval: evalService, kb: kb}
+
+		c, r := mockGin()
+		c.Request.Body = io.NopCloser(strings.NewReader(`{}`))
+
+		EvalHandler()(c)
+
+		if r.Code != tc.code {
+			t.Errorf("policy %s: expected status %d, got %d: %s", tc.policy, tc.code, r.Code, r.Body.String())
+		}
+
+		if tc.policy == "fallback" && !strings.Contains(r.Body.String(), `"adult":true`) {
+			t.Errorf("expected the feature evaluated with the fallback value, got %s", r.Body.String())
+		}
+	}
+}
+
+// MockExposureSink records the exposures of the experiments.
+type MockExposureSink struct {
+	exposures []*experiment.Exposure
+}
+
+// Expose records the exposure
+func (s *MockExposureSink) Expose(exposure *experiment.Exposure) error {
+	s.exposures = append(s.exposures, exposure)
+	return nil
+}
+
+// This is a test function that checks if the EvalHandler returns the assigned variants of the
+// experiments and emits their exposures to the sink.
+func TestEvalHandlerExperiment(t *testing.T) {
+	sink := &MockExposureSink{}
+	experiment.Default = sink
+	defer func() {
+		experiment.Default = nil
+	}()
+
+	evalService := services.NewEval(config.GetConfig())
+	kb, err := evalService.BuildTemporaryKnowledgeBase(`
+		rule feat_blue_button salience 10 {
+			when
+				true
+			then
+				result.Put("blueButton", result.Experiment("checkout", ctx.Get("customerId"), "control:0", "blue:100") == "blue");
+				Retract("feat_blue_button");
+		}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	services.EvalService = EvalServiceTestEvalHandlerRemoteLoadFailure{IEval: evalService, kb: kb}
+
+	c, r := mockGin()
+	c.Params = gin.Params{{Key: "knowledgeBase", Value: "checkout"}, {Key: "version", Value: "2"}}
+	c.Request.Body = io.NopCloser(strings.NewReader(`{"customerId": 12345}`))
+
+	EvalHandler()(c)
+
+	if r.Code != http.StatusOK {
+		t.Fatalf("got error on request evalHandler func: %d %s", r.Code, r.Body.String())
+	}
+
+	expectedBody := `{"blueButton":true}`
+	if r.Body.String() != expectedBody {
+		t.Errorf("Test Fail, we want %v, we got %v", expectedBody, r.Body.String())
+	}
+
+	expectedExperiments := `{"checkout":"blue"}`
+	if got := r.Header().Get("X-Experiments"); got != expectedExperiments {
+		t.Errorf("Test Fail, we want %v, we got %v", expectedExperiments, got)
+	}
+
+	if len(sink.exposures) != 1 {
+		t.Fatalf("Test Fail, we want %v, we got %v", 1, len(sink.exposures))
+	}
+	exposure := sink.exposures[0]
+	if exposure.Experiment != "checkout" || exposure.Variant != "blue" || exposure.Subject != "12345" || exposure.KnowledgeBase != "checkout" || exposure.Version != "2" {
+		t.Errorf("unexpected exposure: %+v", exposure)
+	}
+}
+
+// TestEvalHandlerNumbers checks if the numbers of the context are decoded without losing precision, so
+// an amount sent as a JSON number is read exactly as a decimal.
+func TestEvalHandlerNumbers(t *testing.T) {
+	evalService := services.NewEval(config.GetConfig())
+	kb, err := evalService.BuildTemporaryKnowledgeBase(`
+		rule feat_balance salience 10 {
+			when
+				true
+			then
+				result.Put("balance", ctx.GetDecimal("balance"));
+				result.Put("age", ctx.GetInt("age"));
+				Retract("feat_balance");
+		}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	services.EvalService = EvalServiceTestEvalHandlerRemoteLoadFailure{IEval: evalService, kb: kb}
+
+	c, r := mockGin()
+	c.Request.Body = io.NopCloser(strings.NewReader(`{"balance": 12345678901234567.89, "age": 30}`))
+
+	EvalHandler()(c)
+
+	if r.Code != http.StatusOK {
+		t.Fatalf("got error on request evalHandler func: %d %s", r.Code, r.Body.String())
+	}
+
+	expectedBody := `{"age":30,"balance":12345678901234567.89}`
+	if r.Body.String() != expectedBody {
+		t.Errorf("Test Fail, we want %v, we got %v", expectedBody, r.Body.String())
+	}
+}
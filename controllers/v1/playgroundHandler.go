@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	payloads "github.com/bancodobrasil/featws-ruller/payloads/v1"
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// PlaygroundHandler godoc
+// @Summary 		Evaluate an uploaded rulesheet / Avaliação de uma folha de regra enviada
+// @Description     Constrói a folha de regra enviada em **grl** em uma base de conhecimento temporária e isolada e a avalia com os parâmetros de **context**, sem publicá-la no carregador de recursos.
+// @Description
+// @Description		Os parâmetros carregados remotamente podem ser simulados em **resolvers**, informando o valor de cada parâmetro por resolver. Nesse caso, o resolver bridge nunca é chamado. Enviando **explain** como *true*, é retornado também o rastro das regras avaliadas e executadas pelo motor. As variantes dos experimentos atribuídas são retornadas em **experiments**, sem emitir as exposições.
+// @Description
+// @Description		A avaliação é limitada pelo número máximo de ciclos (*FEATWS_RULLER_PLAYGROUND_MAX_CYCLE*) e pelo tempo máximo em milissegundos (*FEATWS_RULLER_PLAYGROUND_TIMEOUT*).
+// @Tags 			playground
+// @Accept  		json
+// @Produce  		json
+// @Param  			parameters body payloads.Playground true "Rulesheet and parameters"
+// @Success 		200 {object} payloads.PlaygroundResult
+// @Failure 		400 {object} string
+// @Failure 		500 {object} string
+// @Failure 		502 {object} string
+// @Failure 		default {object} string
+// @Security 		Authentication Api Key
+// @Router 			/playground [post]
+// This function handles requests to evaluate an uploaded rulesheet and returns the result as a JSON object.
+func PlaygroundHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig()
+
+		decoder := json.NewDecoder(c.Request.Body)
+		decoder.UseNumber()
+		var t payloads.Playground
+		err := decoder.Decode(&t)
+		if err != nil {
+			log.Errorf("Erro on json decode: %v", err)
+			c.Status(http.StatusInternalServerError)
+			fmt.Fprint(c.Writer, "Error on json decode")
+			return
+		}
+		log.Traceln(t)
+
+		knowledgeBase, err := services.EvalService.BuildTemporaryKnowledgeBase(t.GRL)
+		if err != nil {
+			log.Debugf("Error on build GRL: %v", err)
+			c.String(http.StatusBadRequest, fmt.Sprintf("Error on build GRL: %v", err))
+			return
+		}
+
+		if t.Context == nil {
+			t.Context = make(map[string]interface{})
+		}
+
+		ctx := types.NewContextFromMap(t.Context)
+		ctx.RawContext = c.Request.Context()
+		if t.Resolvers != nil {
+			ctx.Resolver = types.StubResolver(t.Resolvers)
+		}
+
+		opts := services.EvalOptions{
+			MaxCycle: uint64(cfg.PlaygroundMaxCycle),
+			Timeout:  time.Duration(cfg.PlaygroundTimeout) * time.Millisecond,
+			Explain:  t.Explain,
+		}
+
+		result, trace, err := services.EvalService.EvalWithOptions(ctx, knowledgeBase, opts)
+		if respondRemoteLoadError(c, err) {
+			return
+		}
+		if err != nil {
+			log.Debugf("Error on eval: %v", err)
+			c.String(http.StatusBadRequest, fmt.Sprintf("Error on eval: %v", err))
+			return
+		}
+
+		response := payloads.PlaygroundResult{
+			Features:    result.GetFeatures(),
+			Experiments: result.Experiments(),
+		}
+
+		if trace != nil {
+			response.Trace = trace.Entries
+		}
+
+		c.JSON(responseStatus(result), response)
+	}
+}
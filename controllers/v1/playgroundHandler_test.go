@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	payloads "github.com/bancodobrasil/featws-ruller/payloads/v1"
+	"github.com/bancodobrasil/featws-ruller/services"
+)
+
+// playgroundGRL is a rulesheet with a remote loaded param and a required param, like the ones
+// generated by the featws-transpiler.
+const playgroundGRL = `
+	rule DefaultValues salience 10 {
+		when
+			true
+		then
+			ctx.RegistryRemoteLoaded("age", "customer");
+			ctx.RegistryRequiredParams("mynumber");
+			Retract("DefaultValues");
+	}
+
+	rule feat_adult salience 9 {
+		when
+			true
+		then
+			result.Put("adult", ctx.GetInt("age") >= 18);
+			result.Put("small", ctx.GetInt("mynumber") < 12);
+			Retract("feat_adult");
+	}
+`
+
+// This is a test function that checks if the PlaygroundHandler evaluates an uploaded rulesheet with
+// stubbed resolvers and returns the features and the trace.
+func TestPlaygroundHandler(t *testing.T) {
+	services.EvalService = services.NewEval(config.GetConfig())
+
+	body, _ := json.Marshal(payloads.Playground{
+		GRL: playgroundGRL,
+		Context: map[string]interface{}{
+			"mynumber": "5",
+		},
+		Resolvers: map[string]map[string]interface{}{
+			"customer": {"age": 21},
+		},
+		Explain: true,
+	})
+
+	c, r := mockGin()
+	c.Request.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	PlaygroundHandler()(c)
+
+	if r.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, r.Code, r.Body.String())
+	}
+
+	var got payloads.PlaygroundResult
+	err := json.Unmarshal(r.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Features["adult"] != true || got.Features["small"] != true {
+		t.Errorf("unexpected features: %v", got.Features)
+	}
+
+	if len(got.Trace) == 0 {
+		t.Error("expected the trace of the evaluation")
+	}
+
+	if len(services.EvalService.GetKnowledgeLibrary().Library) != 0 {
+		t.Error("the uploaded rulesheet must not be stored on the shared knowledge library")
+	}
+}
+
+// This is a test function that checks if the PlaygroundHandler returns a bad request when the
+// uploaded rulesheet can't be built.
+func TestPlaygroundHandlerBuildError(t *testing.T) {
+	services.EvalService = services.NewEval(config.GetConfig())
+
+	body, _ := json.Marshal(payloads.Playground{
+		GRL: "rule broken {",
+	})
+
+	c, r := mockGin()
+	c.Request.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	PlaygroundHandler()(c)
+
+	if r.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, r.Code)
+	}
+
+	if !strings.HasPrefix(r.Body.String(), "Error on build GRL") {
+		t.Errorf("unexpected body: %s", r.Body.String())
+	}
+}
+
+// This is a test function that checks if the PlaygroundHandler aborts a rulesheet that never ends
+// when it reaches the maximum number of cycles.
+func TestPlaygroundHandlerMaxCycle(t *testing.T) {
+	services.EvalService = services.NewEval(config.GetConfig())
+
+	body, _ := json.Marshal(payloads.Playground{
+		GRL: `
+			rule Forever salience 10 {
+				when
+					true
+				then
+					result.Put("forever", true);
+			}
+		`,
+	})
+
+	c, r := mockGin()
+	c.Request.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	PlaygroundHandler()(c)
+
+	if r.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, r.Code)
+	}
+
+	if !strings.HasPrefix(r.Body.String(), "Error on eval") {
+		t.Errorf("unexpected body: %s", r.Body.String())
+	}
+}
+
+// This is a test function that checks if the PlaygroundHandler maps the failures to load a remote param
+// like the EvalHandler, and returns the assigned experiments apart from the features.
+func TestPlaygroundHandlerRemoteLoadFailure(t *testing.T) {
+	services.EvalService = services.NewEval(config.GetConfig())
+
+	body, _ := json.Marshal(payloads.Playground{
+		GRL: `
+			rule feat_blue_button salience 10 {
+				when
+					true
+				then
+					ctx.RegistryRemoteLoadedWithPolicy("age", "customer", "", "fail", 0);
+					result.Put("blueButton", result.Experiment("checkout", ctx.Get("customerId"), "control:0", "blue:100") == "blue");
+					result.Put("adult", ctx.GetInt("age") >= 18);
+					Retract("feat_blue_button");
+			}
+		`,
+		Context:   map[string]interface{}{"customerId": 12345},
+		Resolvers: map[string]map[string]interface{}{"customer": {}},
+	})
+
+	c, r := mockGin()
+	c.Request.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	PlaygroundHandler()(c)
+
+	if r.Code != http.StatusBadGateway {
+		t.Errorf("expected status %d, got %d: %s", http.StatusBadGateway, r.Code, r.Body.String())
+	}
+
+	if !strings.HasPrefix(r.Body.String(), "Error on load remote param") {
+		t.Errorf("unexpected body: %s", r.Body.String())
+	}
+
+	body, _ = json.Marshal(payloads.Playground{
+		GRL: `
+			rule feat_blue_button salience 10 {
+				when
+					true
+				then
+					result.Put("blueButton", result.Experiment("checkout", ctx.Get("customerId"), "control:0", "blue:100") == "blue");
+					Retract("feat_blue_button");
+			}
+		`,
+		Context: map[string]interface{}{"customerId": 12345},
+	})
+
+	c, r = mockGin()
+	c.Request.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	PlaygroundHandler()(c)
+
+	expected := `{"features":{"blueButton":true},"experiments":{"checkout":"blue"}}`
+	if r.Code != http.StatusOK || r.Body.String() != expected {
+		t.Errorf("Test Fail, we want %v, we got %d %v", expected, r.Code, r.Body.String())
+	}
+}
@@ -51,11 +51,23 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/v1.Eval"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "no-cache to bypass the cache of the resolver responses",
+                        "name": "Cache-Control",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
+                        "headers": {
+                            "X-Experiments": {
+                                "type": "string",
+                                "description": "the variants of the experiments assigned on the evaluation, by experiment, as a JSON object"
+                            }
+                        },
                         "schema": {
                             "type": "string"
                         }
@@ -78,6 +90,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "default": {
                         "description": "",
                         "schema": {
@@ -120,11 +138,23 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/v1.Eval"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "no-cache to bypass the cache of the resolver responses",
+                        "name": "Cache-Control",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
+                        "headers": {
+                            "X-Experiments": {
+                                "type": "string",
+                                "description": "the variants of the experiments assigned on the evaluation, by experiment, as a JSON object"
+                            }
+                        },
                         "schema": {
                             "type": "string"
                         }
@@ -147,6 +177,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "default": {
                         "description": "",
                         "schema": {
@@ -195,11 +231,23 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/v1.Eval"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "no-cache to bypass the cache of the resolver responses",
+                        "name": "Cache-Control",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
+                        "headers": {
+                            "X-Experiments": {
+                                "type": "string",
+                                "description": "the variants of the experiments assigned on the evaluation, by experiment, as a JSON object"
+                            }
+                        },
                         "schema": {
                             "type": "string"
                         }
@@ -222,6 +270,75 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playground": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    }
+                ],
+                "description": "Constrói a folha de regra enviada em **grl** em uma base de conhecimento temporária e isolada e a avalia com os parâmetros de **context**, sem publicá-la no carregador de recursos.\n\nOs parâmetros carregados remotamente podem ser simulados em **resolvers**, informando o valor de cada parâmetro por resolver. Nesse caso, o resolver bridge nunca é chamado. Enviando **explain** como *true*, é retornado também o rastro das regras avaliadas e executadas pelo motor. As variantes dos experimentos atribuídas são retornadas em **experiments**, sem emitir as exposições.\n\nA avaliação é limitada pelo número máximo de ciclos (*FEATWS_RULLER_PLAYGROUND_MAX_CYCLE*) e pelo tempo máximo em milissegundos (*FEATWS_RULLER_PLAYGROUND_TIMEOUT*).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playground"
+                ],
+                "summary": "Evaluate an uploaded rulesheet / Avaliação de uma folha de regra enviada",
+                "parameters": [
+                    {
+                        "description": "Rulesheet and parameters",
+                        "name": "parameters",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.Playground"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PlaygroundResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "default": {
                         "description": "",
                         "schema": {
@@ -233,9 +350,69 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "services.TraceEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "candidate": {
+                    "type": "boolean"
+                },
+                "cycle": {
+                    "type": "integer"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.Eval": {
             "type": "object",
             "additionalProperties": true
+        },
+        "v1.Playground": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "explain": {
+                    "type": "boolean"
+                },
+                "grl": {
+                    "type": "string"
+                },
+                "resolvers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": true
+                    }
+                }
+            }
+        },
+        "v1.PlaygroundResult": {
+            "type": "object",
+            "properties": {
+                "experiments": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "trace": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.TraceEntry"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
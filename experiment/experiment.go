@@ -0,0 +1,214 @@
+package experiment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	log "github.com/sirupsen/logrus"
+)
+
+// exposures counts the exposures emitted by experiment, variant and outcome, that can be "emitted" or
+// "error".
+var exposures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "featws_ruller_experiment_exposures_total",
+	Help: "Exposures of the subjects to the variants of the experiments by outcome.",
+}, []string{"experiment", "variant", "outcome"})
+
+// Exposure is the event of a subject exposed to the variant of an experiment on an evaluation.
+//
+// Property:
+//   - Experiment: the name of the experiment.
+//   - Variant: the assigned variant.
+//   - Subject: the key of the subject, like a customer id.
+//   - KnowledgeBase: the name of the evaluated knowledge base (rulesheet).
+//   - Version: the evaluated version of the knowledge base.
+//   - Timestamp: the moment of the evaluation.
+type Exposure struct {
+	Experiment    string    `json:"experiment"`
+	Variant       string    `json:"variant"`
+	Subject       string    `json:"subject"`
+	KnowledgeBase string    `json:"knowledgeBase"`
+	Version       string    `json:"version"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Sink receives the exposures of the experiments.
+type Sink interface {
+	Expose(exposure *Exposure) error
+}
+
+// LogSink logs the exposures.
+type LogSink struct{}
+
+// Expose logs the exposure
+func (LogSink) Expose(exposure *Exposure) error {
+	log.WithFields(log.Fields{
+		"experiment":    exposure.Experiment,
+		"variant":       exposure.Variant,
+		"subject":       exposure.Subject,
+		"knowledgeBase": exposure.KnowledgeBase,
+		"version":       exposure.Version,
+	}).Info("Experiment exposure")
+	return nil
+}
+
+// FileSink appends the exposures as JSON lines. It's safe to be used by concurrent requests.
+type FileSink struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewFileSink creates a FileSink that appends the exposures to w
+func NewFileSink(w io.Writer) *FileSink {
+	return &FileSink{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Expose appends the exposure as a JSON line
+func (s *FileSink) Expose(exposure *Exposure) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.encoder.Encode(exposure)
+}
+
+// WebhookSink posts each exposure as JSON to a webhook in the background, so the served requests never
+// wait for it. The exposures beyond the queue are dropped.
+//
+// Property:
+//   - url: the URL of the webhook.
+//   - client: the HTTP client used to post the exposures.
+//   - queue: the exposures waiting to be posted.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan *Exposure
+	wg     sync.WaitGroup
+}
+
+// NewWebhookSink creates a WebhookSink of the URL, with a queue of the given size, and starts posting
+// the exposures
+func NewWebhookSink(url string, queueSize int, timeout time.Duration) *WebhookSink {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	s := &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan *Exposure, queueSize),
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for exposure := range s.queue {
+			err := s.post(exposure)
+			if err != nil {
+				log.Errorf("Error on post the exposure to the webhook: %v", err)
+			}
+		}
+	}()
+	return s
+}
+
+// Expose enqueues the exposure to be posted. It fails when the queue is full
+func (s *WebhookSink) Expose(exposure *Exposure) error {
+	select {
+	case s.queue <- exposure:
+		return nil
+	default:
+		return fmt.Errorf("the exposures queue of the webhook is full")
+	}
+}
+
+// Close stops accepting exposures and waits the queued ones to be posted
+func (s *WebhookSink) Close() {
+	close(s.queue)
+	s.wg.Wait()
+}
+
+// post sends the exposure to the webhook.
+func (s *WebhookSink) post(exposure *Exposure) error {
+	body, err := json.Marshal(exposure)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the webhook responded with the status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Default is the Sink of the exposures of the eval endpoint. It's nil when the exposures are disabled.
+var Default Sink
+
+// Setup sets the Default Sink configured on `FEATWS_RULLER_EXPOSURE_SINK`, that can be "log", "file",
+// appending to `FEATWS_RULLER_EXPOSURE_PATH`, or "webhook", posting to `FEATWS_RULLER_EXPOSURE_WEBHOOK_URL`.
+// It does nothing when there is no sink configured.
+func Setup(cfg *config.Config) error {
+	switch cfg.ExposureSink {
+	case "":
+		return nil
+	case "log":
+		Default = LogSink{}
+	case "file":
+		file, err := os.OpenFile(cfg.ExposurePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("error on open the exposures file: %w", err)
+		}
+		Default = NewFileSink(file)
+	case "webhook":
+		if cfg.ExposureWebhookURL == "" {
+			return fmt.Errorf("the exposures webhook URL isn't configured")
+		}
+		Default = NewWebhookSink(cfg.ExposureWebhookURL, int(cfg.ExposureQueueSize), time.Duration(cfg.ExposureWebhookTimeout)*time.Millisecond)
+	default:
+		return fmt.Errorf("unknown exposure sink '%s'", cfg.ExposureSink)
+	}
+
+	log.Infof("Emitting the experiment exposures to the %s sink", cfg.ExposureSink)
+	return nil
+}
+
+// Emit sends to the sink an exposure of each experiment assigned on the result of the evaluation of the
+// knowledge base. The failures of the sink are logged and never affect the evaluation.
+func Emit(sink Sink, knowledgeBaseName string, version string, result *types.Result) {
+	if sink == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, assignment := range result.Assignments() {
+		err := sink.Expose(&Exposure{
+			Experiment:    assignment.Experiment,
+			Variant:       assignment.Variant,
+			Subject:       assignment.Subject,
+			KnowledgeBase: knowledgeBaseName,
+			Version:       version,
+			Timestamp:     now,
+		})
+		if err != nil {
+			log.Errorf("Error on emit the exposure of the experiment %s: %v", assignment.Experiment, err)
+			exposures.WithLabelValues(assignment.Experiment, assignment.Variant, "error").Inc()
+			continue
+		}
+		exposures.WithLabelValues(assignment.Experiment, assignment.Variant, "emitted").Inc()
+	}
+}
@@ -0,0 +1,122 @@
+package experiment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+)
+
+// MockSink records the exposures, failing when it has an error.
+type MockSink struct {
+	exposures []*Exposure
+	err       error
+}
+
+// Expose records the exposure
+func (s *MockSink) Expose(exposure *Exposure) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.exposures = append(s.exposures, exposure)
+	return nil
+}
+
+// TestEmit checks if an exposure is emitted for each experiment assigned on the result.
+func TestEmit(t *testing.T) {
+	result := types.NewResult()
+	variant := result.Experiment("checkout", "ana", "control:1", "blue:1")
+	result.Experiment("layout", "ana", "modern:1")
+
+	sink := &MockSink{}
+	Emit(sink, "mykb", "3", result)
+
+	if len(sink.exposures) != 2 {
+		t.Fatalf("Test Fail, we want %v, we got %v", 2, len(sink.exposures))
+	}
+
+	exposure := sink.exposures[0]
+	if exposure.Experiment != "checkout" || exposure.Variant != variant || exposure.Subject != "ana" || exposure.KnowledgeBase != "mykb" || exposure.Version != "3" || exposure.Timestamp.IsZero() {
+		t.Errorf("unexpected exposure: %+v", exposure)
+	}
+
+	Emit(nil, "mykb", "3", result)
+	Emit(&MockSink{err: fmt.Errorf("sink error")}, "mykb", "3", result)
+}
+
+// TestFileSink checks if the exposures are appended as JSON lines.
+func TestFileSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewFileSink(&buf)
+
+	for _, variant := range []string{"control", "blue"} {
+		err := sink.Expose(&Exposure{Experiment: "checkout", Variant: variant, Subject: "ana"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decoder := json.NewDecoder(&buf)
+	for _, expected := range []string{"control", "blue"} {
+		exposure := &Exposure{}
+		err := decoder.Decode(exposure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exposure.Variant != expected {
+			t.Errorf("Test Fail, we want %v, we got %v", expected, exposure.Variant)
+		}
+	}
+}
+
+// TestWebhookSink checks if the exposures are posted to the webhook and dropped when the queue is full.
+func TestWebhookSink(t *testing.T) {
+	var mutex sync.Mutex
+	received := []*Exposure{}
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		exposure := &Exposure{}
+		err := json.NewDecoder(r.Body).Decode(exposure)
+		if err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mutex.Lock()
+		received = append(received, exposure)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, 1, time.Second)
+
+	// the first exposure is taken by the worker, the second one waits on the queue and the third is dropped
+	err := sink.Expose(&Exposure{Experiment: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(sink.queue) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := sink.Expose(&Exposure{Experiment: "second"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Expose(&Exposure{Experiment: "third"}); err == nil {
+		t.Error("expected an error when the queue is full")
+	}
+
+	close(release)
+	sink.Close()
+
+	if len(received) != 2 || received[0].Experiment != "first" || received[1].Experiment != "second" {
+		t.Errorf("unexpected exposures: %v", received)
+	}
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"context"
+	"os"
 
+	"github.com/bancodobrasil/featws-ruller/capture"
+	"github.com/bancodobrasil/featws-ruller/cmd"
 	"github.com/bancodobrasil/featws-ruller/config"
 	_ "github.com/bancodobrasil/featws-ruller/docs"
+	"github.com/bancodobrasil/featws-ruller/experiment"
+	"github.com/bancodobrasil/featws-ruller/processor"
 	"github.com/bancodobrasil/featws-ruller/routes"
 	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/shadow"
+	"github.com/bancodobrasil/featws-ruller/types"
 	ginMonitor "github.com/bancodobrasil/gin-monitor"
 	"github.com/bancodobrasil/goauth"
 	logAuth "github.com/bancodobrasil/goauth/log"
@@ -69,7 +76,8 @@ func setupLog(config *config.Config) {
 // @x-extension-openapi {"example": "value on a json format"}
 
 // This function sets up a server using the Gin framework and loads default rules if specified in the
-// configuration.
+// configuration. When the first argument is the name of a subcommand, like `eval`, it runs the
+// subcommand offline instead of the server.
 func main() {
 
 	err := config.LoadConfig()
@@ -81,6 +89,22 @@ func main() {
 
 	setupLog(cfg)
 
+	err = processor.SetupGeo(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.ResolverPluginsPath != "" {
+		err = types.LoadResolverPlugins(cfg.ResolverPluginsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(os.Args) > 1 && cmd.Has(os.Args[1]) {
+		os.Exit(cmd.Run(os.Args[1:]))
+	}
+
 	if cfg.DefaultRules != "" {
 		defaultGRL := cfg.DefaultRules
 		log.Debugf("Carregando '%s' como folha de regras default!", defaultGRL)
@@ -92,6 +116,18 @@ func main() {
 		log.Warnln("Não foram carregadas regras default!")
 	}
 
+	err = capture.Setup(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shadow.Setup(cfg, services.EvalService)
+
+	err = experiment.Setup(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	monitor, err := ginMonitor.New("v0.3.2-rc1", ginMonitor.DefaultErrorMessageKey, ginMonitor.DefaultBuckets)
 	if err != nil {
 		log.Panic(err)
@@ -0,0 +1,29 @@
+package v1
+
+import "github.com/bancodobrasil/featws-ruller/services"
+
+// Playground is the payload to evaluate an uploaded rulesheet.
+//
+// Property:
+//   - GRL: the source of the rulesheet to be evaluated.
+//   - Context: the parameters of the evaluation, like the body of the eval endpoint.
+//   - Resolvers: optional stubbed values of the remote loaded params, by resolver name and param. When it's informed, the resolver bridge is never called.
+//   - Explain: indicates whether the trace of the evaluated and executed rules must be returned.
+type Playground struct {
+	GRL       string                            `json:"grl"`
+	Context   map[string]interface{}            `json:"context"`
+	Resolvers map[string]map[string]interface{} `json:"resolvers,omitempty"`
+	Explain   bool                              `json:"explain,omitempty"`
+}
+
+// PlaygroundResult is the response of the evaluation of an uploaded rulesheet.
+//
+// Property:
+//   - Features: the features returned by the rulesheet.
+//   - Experiments: the variants of the experiments assigned on the evaluation, by experiment. Their exposures aren't emitted.
+//   - Trace: the rules evaluated and executed by the engine, when explain was requested.
+type PlaygroundResult struct {
+	Features    map[string]interface{} `json:"features"`
+	Experiments map[string]string      `json:"experiments,omitempty"`
+	Trace       []services.TraceEntry  `json:"trace,omitempty"`
+}
@@ -0,0 +1,289 @@
+package processor
+
+import (
+	_ "embed" // the embedded Brazilian dataset
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	log "github.com/sirupsen/logrus"
+)
+
+//go:embed data/states.csv
+var statesCSV string
+
+//go:embed data/municipalities.csv
+var municipalitiesCSV string
+
+// state is a Brazilian state on the IBGE dataset.
+//
+// Property:
+//   - code: the IBGE code of the state, the first two digits of the codes of its municipalities.
+//   - uf: the abbreviation of the state, like "SP".
+//   - name: the name of the state.
+//   - region: the geographic region of the state, like "Sudeste".
+type state struct {
+	code   int64
+	uf     string
+	name   string
+	region string
+}
+
+// municipality is a Brazilian municipality on the IBGE dataset.
+//
+// Property:
+//   - code: the 7 digits IBGE code of the municipality.
+//   - name: the name of the municipality.
+//   - uf: the abbreviation of the state of the municipality.
+//   - lat: the latitude of the seat of the municipality.
+//   - lon: the longitude of the seat of the municipality.
+type municipality struct {
+	code int64
+	name string
+	uf   string
+	lat  float64
+	lon  float64
+}
+
+// StateName method returns the name of the Brazilian state of the abbreviation, like "São Paulo" for
+// "SP", or an empty string when it isn't a state
+func (p *Processor) StateName(uf string) string {
+	if s, ok := getStates()[strings.ToUpper(strings.TrimSpace(uf))]; ok {
+		return s.name
+	}
+	return ""
+}
+
+// StateRegion method returns the geographic region of the Brazilian state of the abbreviation: "Norte",
+// "Nordeste", "Centro-Oeste", "Sudeste" or "Sul", or an empty string when it isn't a state
+func (p *Processor) StateRegion(uf string) string {
+	if s, ok := getStates()[strings.ToUpper(strings.TrimSpace(uf))]; ok {
+		return s.region
+	}
+	return ""
+}
+
+// MunicipalityState method returns the abbreviation of the state of the municipality of the IBGE code,
+// given by its first two digits, or an empty string when it isn't a code of a state
+func (p *Processor) MunicipalityState(code interface{}) string {
+	prefix := municipalityCode(code) / 10000
+	for _, s := range getStates() {
+		if s.code == prefix {
+			return s.uf
+		}
+	}
+	return ""
+}
+
+// MunicipalityName method returns the name of the municipality of the IBGE code, with 7 digits or
+// without the check digit, or an empty string when it isn't on the dataset
+func (p *Processor) MunicipalityName(code interface{}) string {
+	value := municipalityCode(code)
+	for _, m := range getMunicipalities() {
+		if m.code/10 == value {
+			return m.name
+		}
+	}
+	return ""
+}
+
+// MunicipalityCode method returns the IBGE code of the municipality of the state, compared ignoring the
+// case and the accents, or zero when it isn't on the dataset
+func (p *Processor) MunicipalityCode(name string, uf string) int64 {
+	name = foldName(name)
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	for _, m := range getMunicipalities() {
+		if m.uf == uf && foldName(m.name) == name {
+			return m.code
+		}
+	}
+	return 0
+}
+
+// NearestMunicipality method returns the IBGE code of the municipality whose seat is the nearest to the
+// point, given by its latitude and longitude. It needs the table with all the municipalities on
+// `FEATWS_RULLER_GEO_MUNICIPALITIES`, and panics without it, because the nearest municipality of the
+// embedded dataset is usually a wrong one
+func (p *Processor) NearestMunicipality(lat interface{}, lon interface{}) int64 {
+	if config.GetConfig().GeoMunicipalitiesPath == "" {
+		log.Panic("NearestMunicipality needs the municipalities of FEATWS_RULLER_GEO_MUNICIPALITIES")
+	}
+
+	y, x := coordinate(lat), coordinate(lon)
+
+	nearest := int64(0)
+	shortest := math.Inf(1)
+	for _, m := range getMunicipalities() {
+		if d := distance(y, x, m.lat, m.lon); d < shortest {
+			nearest, shortest = m.code, d
+		}
+	}
+	return nearest
+}
+
+// municipalityCode returns the IBGE code of the value without the check digit, so the codes with 7 or
+// 6 digits are compared by their first 6 digits.
+func municipalityCode(value interface{}) int64 {
+	code := number(value).IntPart()
+	if code >= 1000000 {
+		code /= 10
+	}
+	return code
+}
+
+// accents replaces the accented letters of the Portuguese names.
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// foldName returns the name in lower case, without accents and extra spaces.
+func foldName(name string) string {
+	return accents.Replace(strings.Join(strings.Fields(strings.ToLower(name)), " "))
+}
+
+// readCSV reads the records of a CSV with a header, checking the number of columns.
+func readCSV(reader io.Reader, columns int) ([][]string, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = columns
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the CSV hasn't a header")
+	}
+	return records[1:], nil
+}
+
+// parseStates parses the states of the CSV with the columns code, uf, name and region.
+func parseStates(reader io.Reader) (map[string]*state, error) {
+	records, err := readCSV(reader, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]*state)
+	for _, record := range records {
+		code, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code of the state %s: %w", record[1], err)
+		}
+		states[record[1]] = &state{code: code, uf: record[1], name: record[2], region: record[3]}
+	}
+	return states, nil
+}
+
+// parseMunicipalities parses the municipalities of the CSV with the columns code, name, uf, latitude and
+// longitude.
+func parseMunicipalities(reader io.Reader) ([]*municipality, error) {
+	records, err := readCSV(reader, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	municipalities := make([]*municipality, 0, len(records))
+	for _, record := range records {
+		code, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code of the municipality %s: %w", record[1], err)
+		}
+		lat, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude of the municipality %s: %w", record[1], err)
+		}
+		lon, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude of the municipality %s: %w", record[1], err)
+		}
+		municipalities = append(municipalities, &municipality{code: code, name: record[1], uf: strings.ToUpper(record[2]), lat: lat, lon: lon})
+	}
+	return municipalities, nil
+}
+
+// embeddedStates are the states of the embedded dataset by abbreviation.
+var embeddedStates map[string]*state
+
+var statesOnce sync.Once
+
+// getStates returns the embedded states by abbreviation.
+func getStates() map[string]*state {
+	statesOnce.Do(func() {
+		var err error
+		embeddedStates, err = parseStates(strings.NewReader(statesCSV))
+		if err != nil {
+			log.WithError(err).Panic("error on load the embedded states")
+		}
+	})
+	return embeddedStates
+}
+
+// municipalitiesLoad is the outcome of loading a dataset of municipalities.
+//
+// Property:
+//   - municipalities: the loaded municipalities.
+//   - err: the error of the load, cached so a broken file isn't read again on every use.
+type municipalitiesLoad struct {
+	municipalities []*municipality
+	err            error
+}
+
+var municipalitiesMutex sync.Mutex
+
+// loadedMunicipalities are the outcomes of loading the municipalities by path, with the embedded dataset
+// on the empty path.
+var loadedMunicipalities = make(map[string]*municipalitiesLoad)
+
+// municipalitiesOf returns the municipalities of the CSV file or, on the empty path, of the embedded
+// dataset, loaded once by path, even when it fails.
+func municipalitiesOf(path string) ([]*municipality, error) {
+	municipalitiesMutex.Lock()
+	defer municipalitiesMutex.Unlock()
+
+	loaded, ok := loadedMunicipalities[path]
+	if !ok {
+		loaded = &municipalitiesLoad{}
+		loaded.municipalities, loaded.err = loadMunicipalities(path)
+		loadedMunicipalities[path] = loaded
+	}
+	return loaded.municipalities, loaded.err
+}
+
+// loadMunicipalities parses the municipalities of the CSV file or, on the empty path, of the embedded
+// dataset.
+func loadMunicipalities(path string) ([]*municipality, error) {
+	if path == "" {
+		return parseMunicipalities(strings.NewReader(municipalitiesCSV))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseMunicipalities(file)
+}
+
+// getMunicipalities returns the municipalities of the CSV of `FEATWS_RULLER_GEO_MUNICIPALITIES` or,
+// when it isn't set, of the embedded dataset of the capitals and largest municipalities.
+func getMunicipalities() []*municipality {
+	path := config.GetConfig().GeoMunicipalitiesPath
+
+	municipalities, err := municipalitiesOf(path)
+	if err != nil {
+		log.WithError(err).Panicf("error on load the municipalities %s", path)
+	}
+	return municipalities
+}
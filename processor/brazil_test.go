@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// TestStates checks if the Brazilian states are looked up by their abbreviations.
+func TestStates(t *testing.T) {
+	p := NewProcessor()
+
+	if got := len(getStates()); got != 27 {
+		t.Errorf("Test Fail, we want %v, we got %v", 27, got)
+	}
+	if got := p.StateName("sp"); got != "São Paulo" {
+		t.Errorf("Test Fail, we want %v, we got %v", "São Paulo", got)
+	}
+	if got := p.StateRegion(" DF "); got != "Centro-Oeste" {
+		t.Errorf("Test Fail, we want %v, we got %v", "Centro-Oeste", got)
+	}
+	if got := p.StateName("XX"); got != "" {
+		t.Errorf("Test Fail, we want %v, we got %v", "", got)
+	}
+}
+
+// TestMunicipalities checks if the municipalities are looked up by their IBGE codes and names on the
+// embedded dataset.
+func TestMunicipalities(t *testing.T) {
+	p := NewProcessor()
+
+	for code, expected := range map[interface{}]string{
+		3550308:   "São Paulo",
+		"3304557": "Rio de Janeiro",
+		330455:    "Rio de Janeiro",
+		5300108.0: "Brasília",
+		3599999:   "",
+	} {
+		if got := p.MunicipalityName(code); got != expected {
+			t.Errorf("Test Fail on %v, we want %v, we got %v", code, expected, got)
+		}
+	}
+
+	// The state comes from the code, even for the municipalities that aren't on the dataset
+	for code, expected := range map[interface{}]string{3550308: "SP", 2927408: "BA", 1500107: "PA", 291080: "BA", 9999999: ""} {
+		if got := p.MunicipalityState(code); got != expected {
+			t.Errorf("Test Fail on %v, we want %v, we got %v", code, expected, got)
+		}
+	}
+
+	if got := p.MunicipalityCode("  sao   PAULO ", "sp"); got != 3550308 {
+		t.Errorf("Test Fail, we want %v, we got %v", 3550308, got)
+	}
+	if got := p.MunicipalityCode("Florianopolis", "SC"); got != 4205407 {
+		t.Errorf("Test Fail, we want %v, we got %v", 4205407, got)
+	}
+	if got := p.MunicipalityCode("São Paulo", "RJ"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+}
+
+// TestNearestMunicipalityWithoutFile checks if NearestMunicipality panics without the dataset of
+// FEATWS_RULLER_GEO_MUNICIPALITIES, instead of using the embedded one.
+func TestNearestMunicipalityWithoutFile(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic without the municipalities file")
+		}
+	}()
+	NewProcessor().NearestMunicipality(-23.5614, -46.6559)
+}
+
+// TestEmbeddedMunicipalities checks if the embedded dataset is consistent with the states.
+func TestEmbeddedMunicipalities(t *testing.T) {
+	p := NewProcessor()
+
+	for _, m := range getMunicipalities() {
+		if got := p.MunicipalityState(m.code); got != m.uf {
+			t.Errorf("Test Fail on %s, we want %v, we got %v", m.name, m.uf, got)
+		}
+		if m.lat < -34 || m.lat > 6 || m.lon < -74 || m.lon > -34 {
+			t.Errorf("the municipality %s is outside Brazil: %v,%v", m.name, m.lat, m.lon)
+		}
+	}
+}
+
+// TestMunicipalitiesFile checks if the dataset of FEATWS_RULLER_GEO_MUNICIPALITIES replaces the embedded one.
+func TestMunicipalitiesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "municipalities.csv")
+	err := os.WriteFile(path, []byte("code,name,uf,latitude,longitude\n"+
+		"3500105,Adamantina,SP,-21.6820,-51.0737\n"+
+		"3550308,São Paulo,SP,-23.5329,-46.6395\n"+
+		"3548708,São Bernardo do Campo,SP,-23.6914,-46.5646\n"+
+		"2927408,Salvador,BA,-12.9718,-38.5011\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.GetConfig().GeoMunicipalitiesPath = path
+	t.Cleanup(func() {
+		config.LoadConfig()
+	})
+
+	p := NewProcessor()
+	if got := p.MunicipalityName(3500105); got != "Adamantina" {
+		t.Errorf("Test Fail, we want %v, we got %v", "Adamantina", got)
+	}
+	if got := p.MunicipalityName(3304557); got != "" {
+		t.Errorf("Test Fail, we want %v, we got %v", "", got)
+	}
+
+	// A point on Avenida Paulista, another near the Pelourinho and another in São Bernardo do Campo
+	for _, test := range []struct {
+		lat      interface{}
+		lon      interface{}
+		expected int64
+	}{
+		{-23.5614, -46.6559, 3550308},
+		{"-12.9735", "-38.5080", 2927408},
+		{-23.7000, -46.5500, 3548708},
+	} {
+		if got := p.NearestMunicipality(test.lat, test.lon); got != test.expected {
+			t.Errorf("Test Fail, we want %v, we got %v", test.expected, got)
+		}
+	}
+}
+
+// TestInvalidMunicipalities checks if the invalid datasets fail to parse.
+func TestInvalidMunicipalities(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":             "",
+		"missing column":    "code,name,uf,latitude,longitude\n3500105,Adamantina,SP,-21.6820\n",
+		"invalid code":      "code,name,uf,latitude,longitude\nabc,Adamantina,SP,-21.6820,-51.0737\n",
+		"invalid latitude":  "code,name,uf,latitude,longitude\n3500105,Adamantina,SP,north,-51.0737\n",
+		"invalid longitude": "code,name,uf,latitude,longitude\n3500105,Adamantina,SP,-21.6820,west\n",
+	} {
+		_, err := parseMunicipalities(strings.NewReader(content))
+		if err == nil {
+			t.Errorf("expected an error on the municipalities with %s", name)
+		}
+	}
+}
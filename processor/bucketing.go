@@ -0,0 +1,37 @@
+package processor
+
+import (
+	"github.com/bancodobrasil/featws-ruller/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// percentageBuckets is the number of buckets of the percentages, so they have a precision of 0.01%.
+const percentageBuckets = 10000
+
+// Bucket method returns the bucket, from 0 to buckets - 1, of the key, like a customer id, on the
+// feature named by the salt. The same key always lands on the same bucket
+func (p *Processor) Bucket(key interface{}, salt string, buckets int64) int64 {
+	if buckets <= 0 {
+		log.Panicf("invalid number of buckets %d", buckets)
+	}
+	return int64(types.HashKey(key, salt) % uint32(buckets))
+}
+
+// InPercentage method checks if the key, like a customer id, is on the first percentage, from 0 to 100,
+// of the rollout of the feature named by the salt. Increasing the percentage keeps the keys that
+// were already on the rollout
+func (p *Processor) InPercentage(key interface{}, salt string, percentage interface{}) bool {
+	bucket := p.Bucket(key, salt, percentageBuckets)
+	return float64(bucket) < number(percentage).Float64()*percentageBuckets/100
+}
+
+// Variant method selects the variant of a multivariate feature, named by the salt, for the key, like
+// a customer id. The variants are informed as "name:weight", like `processor.Variant(id, "checkout",
+// "control:50", "new:50")`, and each one gets a share of the keys proportional to its weight
+func (p *Processor) Variant(key interface{}, salt string, variants ...string) string {
+	variant, err := types.SelectVariant(key, salt, variants...)
+	if err != nil {
+		log.Panic(err.Error())
+	}
+	return variant
+}
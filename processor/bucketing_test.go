@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+// distributionKeys is the number of keys of the distribution tests.
+const distributionKeys = 100000
+
+// TestBucketStable checks if a key always lands on the same bucket, regardless of its type, and if the
+// salt changes the bucket.
+func TestBucketStable(t *testing.T) {
+	p := NewProcessor()
+
+	if got := p.Bucket("12345", "new-checkout", 100); got != p.Bucket(float64(12345), "new-checkout", 100) || got != p.Bucket(12345, "new-checkout", 100) {
+		t.Error("expected the same bucket to the number and the string of the key")
+	}
+
+	// the buckets are fixed, so a change on the hash that moves the customers between buckets fails here
+	if got := p.Bucket("12345", "new-checkout", 100); got != 69 {
+		t.Errorf("Test Fail, we want %v, we got %v", 69, got)
+	}
+	if got := p.Bucket("98765", "new-checkout", 100); got != 77 {
+		t.Errorf("Test Fail, we want %v, we got %v", 77, got)
+	}
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		if p.Bucket(i, "feature-a", 100) != p.Bucket(i, "feature-b", 100) {
+			moved++
+		}
+	}
+	if moved < 900 {
+		t.Errorf("expected the salts to make independent buckets, only %d of 1000 keys moved", moved)
+	}
+}
+
+// TestBucketDistribution checks if 100000 sequential ids are evenly distributed on 10 buckets, each one
+// within 5% of the expected 10000 keys.
+func TestBucketDistribution(t *testing.T) {
+	p := NewProcessor()
+
+	counts := make([]int, 10)
+	for i := 0; i < distributionKeys; i++ {
+		counts[p.Bucket(strconv.Itoa(i), "distribution", 10)]++
+	}
+
+	for bucket, count := range counts {
+		if math.Abs(float64(count)-distributionKeys/10) > distributionKeys/10*0.05 {
+			t.Errorf("the bucket %d got %d keys, expected about %d", bucket, count, distributionKeys/10)
+		}
+	}
+}
+
+// TestInPercentage checks if the rollout gets the percentage of the keys, within 0.5 percentage
+// points, and if the keys on a rollout stay on it when the percentage grows.
+func TestInPercentage(t *testing.T) {
+	p := NewProcessor()
+
+	for _, percentage := range []float64{0, 1, 12.5, 50, 100} {
+		count := 0
+		for i := 0; i < distributionKeys; i++ {
+			if p.InPercentage(i, "rollout", percentage) {
+				count++
+			}
+			if p.InPercentage(i, "rollout", percentage) && !p.InPercentage(i, "rollout", percentage+10) {
+				t.Fatalf("the key %d left the rollout when it grew from %v%%", i, percentage)
+			}
+		}
+
+		got := float64(count) * 100 / distributionKeys
+		if math.Abs(got-percentage) > 0.5 {
+			t.Errorf("Test Fail, we want %v%%, we got %v%%", percentage, got)
+		}
+	}
+}
+
+// TestVariant checks if the variants get a share of the keys proportional to their weights, within 1
+// percentage point.
+func TestVariant(t *testing.T) {
+	p := NewProcessor()
+
+	counts := map[string]int{}
+	for i := 0; i < distributionKeys; i++ {
+		counts[p.Variant(i, "checkout", "control:50", "blue:30", "green:20", "off:0")]++
+	}
+
+	expected := map[string]float64{"control": 50, "blue": 30, "green": 20, "off": 0}
+	for variant, share := range expected {
+		got := float64(counts[variant]) * 100 / distributionKeys
+		if math.Abs(got-share) > 1 {
+			t.Errorf("Test Fail on %s, we want %v%%, we got %v%%", variant, share, got)
+		}
+	}
+
+	if p.Variant("42", "checkout", "a:1", "b:1") != p.Variant(42, "checkout", "a:1", "b:1") {
+		t.Error("expected the same variant to the same key")
+	}
+
+	for name, variants := range map[string][]string{
+		"no weight":       {"control"},
+		"invalid weight":  {"control:x"},
+		"negative weight": {"control:-1"},
+		"zero weights":    {"control:0", "new:0"},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic on %s", name)
+				}
+			}()
+			p.Variant(1, "checkout", variants...)
+		}()
+	}
+}
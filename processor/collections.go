@@ -0,0 +1,246 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// equal checks if the values are equal, tolerating their types: a number is equal to a number or a
+// numeric string with the same value, like 1, 1.0 and "1", and the other scalars when they have the same
+// representation, like true and "true". Two strings are compared exactly, so the codes "0001" and "1"
+// are different.
+func equal(a interface{}, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+
+	if isNumber(a) || isNumber(b) {
+		da, errA := types.ToDecimal(a)
+		db, errB := types.ToDecimal(b)
+		if errA == nil && errB == nil {
+			return da.Equal(db)
+		}
+	}
+
+	return isScalar(a) && isScalar(b) && fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// isNumber checks if the value is a number, like an int, a float, a JSON number or a decimal, and not a
+// numeric string.
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case json.Number, types.Decimal, *types.Decimal:
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isScalar checks if the value isn't a map, a slice or a pointer.
+func isScalar(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr, reflect.Struct:
+		return false
+	}
+	return true
+}
+
+// indexOf returns the index of the first item of the slice equal to the value, or -1.
+func indexOf(slice []interface{}, value interface{}) int {
+	for i, item := range slice {
+		if equal(item, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// number converts a value to a decimal, panicking when it isn't a number.
+func number(value interface{}) types.Decimal {
+	d, err := types.ToDecimal(value)
+	if err != nil {
+		log.Panicf("the value %v isn't a number", value)
+	}
+	return d
+}
+
+// Contains method to check if entry is into a array. The entries are compared tolerating their types,
+// so 1, 1.0 and "1" are equal
+func (p *Processor) Contains(slice []interface{}, val interface{}) bool {
+	return indexOf(slice, val) >= 0
+}
+
+// In method checks if the value is one of the others, compared like Contains
+func (p *Processor) In(value interface{}, values ...interface{}) bool {
+	return indexOf(values, value) >= 0
+}
+
+// ContainsAny method checks if the slice contains any of the values
+func (p *Processor) ContainsAny(slice []interface{}, values []interface{}) bool {
+	for _, value := range values {
+		if indexOf(slice, value) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll method checks if the slice contains all the values
+func (p *Processor) ContainsAll(slice []interface{}, values []interface{}) bool {
+	for _, value := range values {
+		if indexOf(slice, value) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Distinct method returns the items of the slice without the repeated ones, keeping their order
+func (p *Processor) Distinct(slice []interface{}) []interface{} {
+	result := []interface{}{}
+	for _, item := range slice {
+		if indexOf(result, item) < 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Intersection method returns the distinct items of the first slice that are on the second one
+func (p *Processor) Intersection(a []interface{}, b []interface{}) []interface{} {
+	result := []interface{}{}
+	for _, item := range p.Distinct(a) {
+		if indexOf(b, item) >= 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Union method returns the distinct items of both slices
+func (p *Processor) Union(a []interface{}, b []interface{}) []interface{} {
+	all := make([]interface{}, 0, len(a)+len(b))
+	all = append(all, a...)
+	all = append(all, b...)
+	return p.Distinct(all)
+}
+
+// Difference method returns the distinct items of the first slice that aren't on the second one
+func (p *Processor) Difference(a []interface{}, b []interface{}) []interface{} {
+	result := []interface{}{}
+	for _, item := range p.Distinct(a) {
+		if indexOf(b, item) < 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Len method returns the number of items of the slice
+func (p *Processor) Len(slice []interface{}) int64 {
+	return int64(len(slice))
+}
+
+// Sum method returns the sum of the numbers of the slice, computed as decimals
+func (p *Processor) Sum(slice []interface{}) float64 {
+	sum := types.Decimal{}
+	for _, item := range slice {
+		sum = sum.Add(number(item))
+	}
+	return sum.Float64()
+}
+
+// Avg method returns the average of the numbers of the slice, or zero when it's empty
+func (p *Processor) Avg(slice []interface{}) float64 {
+	if len(slice) == 0 {
+		return 0
+	}
+	return p.Sum(slice) / float64(len(slice))
+}
+
+// Min method returns the least number of the slice. It panics when the slice is empty
+func (p *Processor) Min(slice []interface{}) float64 {
+	return extreme(slice, -1)
+}
+
+// Max method returns the greatest number of the slice. It panics when the slice is empty
+func (p *Processor) Max(slice []interface{}) float64 {
+	return extreme(slice, 1)
+}
+
+// extreme returns the number of the slice that compares to all the others with the given sign.
+func extreme(slice []interface{}, sign int) float64 {
+	if len(slice) == 0 {
+		log.Panic("there isn't a number on an empty slice")
+	}
+
+	result := number(slice[0])
+	for _, item := range slice[1:] {
+		if value := number(item); value.Cmp(result) == sign {
+			result = value
+		}
+	}
+	return result.Float64()
+}
+
+// Sort method returns the items of the slice on ascending order. The numbers are compared by their
+// values and come before the other items, compared by their representation
+func (p *Processor) Sort(slice []interface{}) []interface{} {
+	return sorted(slice, false)
+}
+
+// SortDesc method returns the items of the slice on descending order, like Sort
+func (p *Processor) SortDesc(slice []interface{}) []interface{} {
+	return sorted(slice, true)
+}
+
+// sorted returns a sorted copy of the slice.
+func sorted(slice []interface{}, descending bool) []interface{} {
+	result := make([]interface{}, len(slice))
+	copy(result, slice)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if descending {
+			return compare(result[j], result[i]) < 0
+		}
+		return compare(result[i], result[j]) < 0
+	})
+	return result
+}
+
+// compare compares the values, the numbers by their values before the other values by their
+// representations.
+func compare(a interface{}, b interface{}) int {
+	da, errA := types.ToDecimal(a)
+	db, errB := types.ToDecimal(b)
+	switch {
+	case errA == nil && errB == nil:
+		return da.Cmp(db)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	sa, sb := fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)
+	switch {
+	case sa < sb:
+		return -1
+	case sa > sb:
+		return 1
+	}
+	return 0
+}
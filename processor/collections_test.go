@@ -0,0 +1,141 @@
+package processor
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+)
+
+// TestContainsTolerant checks if the items are compared tolerating their types.
+func TestContainsTolerant(t *testing.T) {
+	p := NewProcessor()
+	slice := []interface{}{1, "2", 3.5, json.Number("4"), true, nil}
+
+	for _, value := range []interface{}{1.0, int64(1), "1", 2, 3.5, "3.50", 4, "true", nil, types.MustParseDecimal("1.0")} {
+		if !p.Contains(slice, value) {
+			t.Errorf("the slice must contain %v", value)
+		}
+	}
+	for _, value := range []interface{}{5, "abc", false, []interface{}{1}} {
+		if p.Contains(slice, value) {
+			t.Errorf("the slice must not contain %v", value)
+		}
+	}
+
+	if !p.In("2", 1, 2, 3) || p.In(4, 1, 2, 3) {
+		t.Error("expected In to compare the values like Contains")
+	}
+	if !p.ContainsAny(slice, []interface{}{9, "1"}) || p.ContainsAll(slice, []interface{}{1, 9}) || !p.ContainsAll(slice, []interface{}{1, 2}) {
+		t.Error("expected ContainsAny and ContainsAll to compare the values like Contains")
+	}
+}
+
+// TestContainsStrings checks if two strings are compared exactly, even when they're numeric, like the
+// zero-padded codes.
+func TestContainsStrings(t *testing.T) {
+	p := NewProcessor()
+
+	tests := []struct {
+		got      bool
+		expected bool
+	}{
+		{p.Contains([]interface{}{"0001"}, "1"), false},
+		{p.Contains([]interface{}{"0001"}, "0001"), true},
+		{p.Contains([]interface{}{"0001"}, 1), true},
+		{p.In("1e3", "1000"), false},
+		{p.In("12.50", "12.5"), false},
+		{p.In("12.50", 12.5), true},
+		{p.In(json.Number("12.50"), "12.5"), true},
+	}
+
+	for i, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("Test Fail %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+}
+
+// TestSetOperations checks the distinct items, the intersection, the union and the difference.
+func TestSetOperations(t *testing.T) {
+	p := NewProcessor()
+	a := []interface{}{1, "2", 2.0, 3, 3}
+	b := []interface{}{"3", 4, 1.0}
+
+	tests := []struct {
+		got      []interface{}
+		expected []interface{}
+	}{
+		{p.Distinct(a), []interface{}{1, "2", 3}},
+		{p.Intersection(a, b), []interface{}{1, 3}},
+		{p.Union(a, b), []interface{}{1, "2", 3, 4}},
+		{p.Difference(a, b), []interface{}{"2"}},
+		{p.Difference([]interface{}{}, b), []interface{}{}},
+	}
+
+	for i, test := range tests {
+		if !reflect.DeepEqual(test.got, test.expected) {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+}
+
+// TestAggregations checks the minimum, the maximum, the sum and the average of the numbers.
+func TestAggregations(t *testing.T) {
+	p := NewProcessor()
+	slice := []interface{}{0.1, "0.2", 3, json.Number("-1.5")}
+
+	tests := []struct {
+		got      float64
+		expected float64
+	}{
+		{p.Sum(slice), 1.8},
+		{p.Avg(slice), 0.45},
+		{p.Min(slice), -1.5},
+		{p.Max(slice), 3},
+		{p.Avg([]interface{}{}), 0},
+		{float64(p.Len(slice)), 4},
+		{p.Sum([]interface{}{0.1, 0.2}), 0.3},
+	}
+
+	for i, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+
+	for name, call := range map[string]func(){
+		"empty slice": func() { p.Max([]interface{}{}) },
+		"not number":  func() { p.Sum([]interface{}{1, "abc"}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic on %s", name)
+				}
+			}()
+			call()
+		}()
+	}
+}
+
+// TestSort checks if the numbers are sorted by their values before the other items.
+func TestSort(t *testing.T) {
+	p := NewProcessor()
+	slice := []interface{}{"b", 10, "2", 1.5, "a"}
+
+	expected := []interface{}{1.5, "2", 10, "a", "b"}
+	if got := p.Sort(slice); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	expected = []interface{}{"b", "a", 10, "2", 1.5}
+	if got := p.SortDesc(slice); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	if slice[0] != "b" {
+		t.Error("the original slice must not be sorted")
+	}
+}
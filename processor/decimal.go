@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"math"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// decimal converts a value to a decimal, panicking when it can't be converted.
+func decimal(value interface{}) types.Decimal {
+	d, err := types.ToDecimal(value)
+	if err != nil {
+		log.Panic(err.Error())
+	}
+	return d
+}
+
+// roundingMode parses a rounding mode, panicking when it's unknown.
+func roundingMode(mode string) types.RoundingMode {
+	parsed, err := types.ParseRoundingMode(mode)
+	if err != nil {
+		log.Panic(err.Error())
+	}
+	return parsed
+}
+
+// Decimal method converts a number or a numeric string, like "1234.56", to a decimal
+func (p *Processor) Decimal(value interface{}) types.Decimal {
+	return decimal(value)
+}
+
+// DecimalAdd method returns the exact sum of the values as a decimal
+func (p *Processor) DecimalAdd(a interface{}, b interface{}) types.Decimal {
+	return decimal(a).Add(decimal(b))
+}
+
+// DecimalSub method returns the exact difference of the values as a decimal
+func (p *Processor) DecimalSub(a interface{}, b interface{}) types.Decimal {
+	return decimal(a).Sub(decimal(b))
+}
+
+// DecimalMul method returns the exact product of the values as a decimal
+func (p *Processor) DecimalMul(a interface{}, b interface{}) types.Decimal {
+	return decimal(a).Mul(decimal(b))
+}
+
+// DecimalDiv method returns the quotient of the values as a decimal with the number of digits after the
+// decimal point, from 0 to 1000, rounded with the mode: HALF_UP, HALF_DOWN, HALF_EVEN, UP, DOWN, CEILING
+// or FLOOR
+func (p *Processor) DecimalDiv(a interface{}, b interface{}, scale int64, mode string) types.Decimal {
+	divisor := decimal(b)
+	if divisor.Sign() == 0 {
+		log.Panic("decimal division by zero")
+	}
+	return decimal(a).Div(divisor, decimalScale(scale), roundingMode(mode))
+}
+
+// DecimalRound method rounds the value to the number of digits after the decimal point, from 0 to 1000,
+// with the mode: HALF_UP, HALF_DOWN, HALF_EVEN, UP, DOWN, CEILING or FLOOR
+func (p *Processor) DecimalRound(value interface{}, scale int64, mode string) types.Decimal {
+	return decimal(value).Round(decimalScale(scale), roundingMode(mode))
+}
+
+// decimalScale converts the scale of the GRL to the scale of a decimal, limiting it to the range of an
+// int32 so it doesn't wrap around. The decimals limit it further.
+func decimalScale(scale int64) int32 {
+	if scale < 0 {
+		return 0
+	}
+	if scale > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(scale)
+}
+
+// DecimalCmp method compares the values as decimals, returning -1, 0 or +1 when the first one is less
+// than, equal to or greater than the second one
+func (p *Processor) DecimalCmp(a interface{}, b interface{}) int64 {
+	return int64(decimal(a).Cmp(decimal(b)))
+}
+
+// DecimalEqual method checks if the values are equal as decimals, regardless of their scales
+func (p *Processor) DecimalEqual(a interface{}, b interface{}) bool {
+	return decimal(a).Equal(decimal(b))
+}
+
+// DecimalLess method checks if the first value is less than the second one as decimals
+func (p *Processor) DecimalLess(a interface{}, b interface{}) bool {
+	return decimal(a).Cmp(decimal(b)) < 0
+}
+
+// DecimalGreater method checks if the first value is greater than the second one as decimals
+func (p *Processor) DecimalGreater(a interface{}, b interface{}) bool {
+	return decimal(a).Cmp(decimal(b)) > 0
+}
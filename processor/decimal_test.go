@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+)
+
+// TestDecimalHelpers checks if the decimal helpers accept decimals, numbers and numeric strings.
+func TestDecimalHelpers(t *testing.T) {
+	p := NewProcessor()
+
+	tests := []struct {
+		got      types.Decimal
+		expected string
+	}{
+		{p.DecimalAdd("0.1", 0.2), "0.3"},
+		{p.DecimalSub(p.Decimal("100.00"), "0.01"), "99.99"},
+		{p.DecimalMul("1500.00", "0.035"), "52.50000"},
+		{p.DecimalDiv(100, 3, 2, "HALF_EVEN"), "33.33"},
+		{p.DecimalRound("52.505", 2, "half_even"), "52.50"},
+		{p.DecimalRound("52.505", 2, "HALF_UP"), "52.51"},
+	}
+
+	for i, test := range tests {
+		if test.got.String() != test.expected {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+
+	if p.DecimalCmp("1.10", 1.1) != 0 || !p.DecimalEqual("2", "2.000") || !p.DecimalLess("0.3", p.DecimalAdd("0.1", "0.2").Add(types.MustParseDecimal("0.01"))) || !p.DecimalGreater(10, "9.99") {
+		t.Error("expected the values to be compared as decimals")
+	}
+}
+
+// TestDecimalHelpersScale checks if the negative and the out of range scales are limited instead of
+// giving wrong results or wrapping around.
+func TestDecimalHelpersScale(t *testing.T) {
+	p := NewProcessor()
+
+	if got := p.DecimalDiv(10, 4, -2, "HALF_EVEN"); !p.DecimalEqual(got, 2) {
+		t.Errorf("Test Fail, we want %v, we got %v with the scale %d", 2, got, got.Scale())
+	}
+	if got := p.DecimalRound("2.567", -1, "HALF_UP").String(); got != "3" {
+		t.Errorf("Test Fail, we want %v, we got %v", "3", got)
+	}
+
+	// 4294967298 wraps around to 2 on a plain int32 conversion
+	if got := p.DecimalDiv(1, 3, 4294967298, "DOWN").Scale(); got != 1000 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1000, got)
+	}
+	if got := p.DecimalRound("1.5", 4294967298, "DOWN").Scale(); got != 1000 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1000, got)
+	}
+}
+
+// TestDecimalHelpersWithPanic checks if the helpers panic on invalid values, rounding modes and
+// divisions by zero.
+func TestDecimalHelpersWithPanic(t *testing.T) {
+	p := NewProcessor()
+
+	calls := map[string]func(){
+		"invalid value":    func() { p.DecimalAdd("abc", 1) },
+		"invalid mode":     func() { p.DecimalRound("1.5", 0, "nearest") },
+		"division by zero": func() { p.DecimalDiv(1, "0.00", 2, "HALF_UP") },
+	}
+
+	for name, call := range calls {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic on %s", name)
+				}
+			}()
+			call()
+		}()
+	}
+}
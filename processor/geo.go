@@ -0,0 +1,262 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// earthRadius is the mean radius of the Earth, in kilometers.
+const earthRadius = 6371.0
+
+// IPInCIDR method checks if the IP, v4 or v6, is on any of the CIDR blocks, like
+// `processor.IPInCIDR(ctx.GetString("ip"), "10.0.0.0/8", "192.168.0.0/16")`. An invalid IP isn't on any
+// block, and an invalid block is logged and never contains an IP, so it doesn't fail the evaluation
+func (p *Processor) IPInCIDR(ip string, cidrs ...string) bool {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+
+	result := false
+	for _, cidr := range cidrs {
+		_, block, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			log.Warnf("invalid CIDR block %s", cidr)
+			continue
+		}
+		if parsed != nil && block.Contains(parsed) {
+			result = true
+		}
+	}
+	return result
+}
+
+// IsPrivateIP method checks if the IP is a private, loopback or link local address
+func (p *Processor) IsPrivateIP(ip string) bool {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	return parsed != nil && (parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsLinkLocalUnicast())
+}
+
+// Distance method returns the great-circle distance, in kilometers, between two points given by their
+// latitudes and longitudes in degrees
+func (p *Processor) Distance(lat1 interface{}, lon1 interface{}, lat2 interface{}, lon2 interface{}) float64 {
+	return distance(coordinate(lat1), coordinate(lon1), coordinate(lat2), coordinate(lon2))
+}
+
+// distance returns the haversine distance, in kilometers, between two points.
+func distance(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	radians := math.Pi / 180
+	dLat := (lat2 - lat1) * radians
+	dLon := (lon2 - lon1) * radians
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*radians)*math.Cos(lat2*radians)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// coordinate returns the coordinate as a float, panicking when it isn't a number.
+func coordinate(value interface{}) float64 {
+	return number(value).Float64()
+}
+
+// InRegion method checks if the point, given by its latitude and longitude, is inside the region of
+// the GeoJSON file of `FEATWS_RULLER_GEO_REGIONS`. A region that isn't configured is logged and never
+// contains a point, so it doesn't fail the evaluation
+func (p *Processor) InRegion(lat interface{}, lon interface{}, name string) bool {
+	for _, r := range getRegions() {
+		if r.name == name {
+			return r.contains(coordinate(lon), coordinate(lat))
+		}
+	}
+	log.Warnf("the region %s isn't configured", name)
+	return false
+}
+
+// Regions method returns the names of the configured regions that contain the point, in the order of
+// the GeoJSON file
+func (p *Processor) Regions(lat interface{}, lon interface{}) []interface{} {
+	x, y := coordinate(lon), coordinate(lat)
+
+	result := []interface{}{}
+	for _, r := range getRegions() {
+		if r.contains(x, y) {
+			result = append(result, r.name)
+		}
+	}
+	return result
+}
+
+// polygon is a polygon of a region, with the outer ring followed by the holes. The positions are
+// [longitude, latitude] pairs, like on GeoJSON.
+type polygon [][][]float64
+
+// region is a named region of the geographic targeting.
+//
+// Property:
+//   - name: the name of the region, from the "name" property of its feature.
+//   - polygons: the polygons of the region.
+type region struct {
+	name     string
+	polygons []polygon
+}
+
+// contains checks if the point is inside any polygon of the region and outside its holes.
+func (r *region) contains(x float64, y float64) bool {
+	for _, poly := range r.polygons {
+		if len(poly) == 0 || !ringContains(poly[0], x, y) {
+			continue
+		}
+
+		inHole := false
+		for _, hole := range poly[1:] {
+			if ringContains(hole, x, y) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains checks if the point is inside the ring by ray casting.
+func ringContains(ring [][]float64, x float64, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// geoJSON is the subset of a GeoJSON feature collection used by the regions.
+type geoJSON struct {
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// loadRegions loads the regions of a GeoJSON feature collection, with Polygon or MultiPolygon features
+// named by their "name" property
+func loadRegions(path string) ([]*region, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection geoJSON
+	err = json.Unmarshal(data, &collection)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := []*region{}
+	for i, feature := range collection.Features {
+		name, ok := feature.Properties["name"].(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("the feature %d of the regions hasn't a name", i)
+		}
+
+		r := &region{name: name}
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var poly polygon
+			err = json.Unmarshal(feature.Geometry.Coordinates, &poly)
+			r.polygons = []polygon{poly}
+		case "MultiPolygon":
+			err = json.Unmarshal(feature.Geometry.Coordinates, &r.polygons)
+		default:
+			err = fmt.Errorf("unsupported geometry %s", feature.Geometry.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid geometry of the region %s: %w", name, err)
+		}
+
+		for _, poly := range r.polygons {
+			for _, ring := range poly {
+				for _, position := range ring {
+					if len(position) < 2 {
+						return nil, fmt.Errorf("invalid position of the region %s: %v", name, position)
+					}
+				}
+			}
+		}
+		regions = append(regions, r)
+	}
+	return regions, nil
+}
+
+// regionsLoad is the outcome of loading a GeoJSON file of regions.
+//
+// Property:
+//   - regions: the loaded regions.
+//   - err: the error of the load, cached so a broken file isn't read again on every use.
+type regionsLoad struct {
+	regions []*region
+	err     error
+}
+
+var regionsMutex sync.Mutex
+
+// loadedRegions are the outcomes of loading the regions by path.
+var loadedRegions = make(map[string]*regionsLoad)
+
+// regionsOf returns the regions of the GeoJSON file, loaded once by path, even when it fails.
+func regionsOf(path string) ([]*region, error) {
+	regionsMutex.Lock()
+	defer regionsMutex.Unlock()
+
+	loaded, ok := loadedRegions[path]
+	if !ok {
+		loaded = &regionsLoad{}
+		loaded.regions, loaded.err = loadRegions(path)
+		loadedRegions[path] = loaded
+	}
+	return loaded.regions, loaded.err
+}
+
+// getRegions returns the regions of `FEATWS_RULLER_GEO_REGIONS`, loaded by SetupGeo or on the first use.
+func getRegions() []*region {
+	path := config.GetConfig().GeoRegionsPath
+	if path == "" {
+		return nil
+	}
+
+	regions, err := regionsOf(path)
+	if err != nil {
+		log.WithError(err).Panicf("error on load the regions %s", path)
+	}
+	return regions
+}
+
+// SetupGeo loads the regions of `FEATWS_RULLER_GEO_REGIONS` and the municipalities of
+// `FEATWS_RULLER_GEO_MUNICIPALITIES`, so a missing or broken file fails the startup instead of the
+// evaluations that use it.
+func SetupGeo(cfg *config.Config) error {
+	if cfg.GeoRegionsPath != "" {
+		_, err := regionsOf(cfg.GeoRegionsPath)
+		if err != nil {
+			return fmt.Errorf("error on load the regions %s: %w", cfg.GeoRegionsPath, err)
+		}
+	}
+
+	_, err := municipalitiesOf(cfg.GeoMunicipalitiesPath)
+	if err != nil {
+		return fmt.Errorf("error on load the municipalities %s: %w", cfg.GeoMunicipalitiesPath, err)
+	}
+	return nil
+}
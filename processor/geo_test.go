@@ -0,0 +1,215 @@
+package processor
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// TestIPInCIDR checks if the IPs are matched against the CIDR blocks.
+func TestIPInCIDR(t *testing.T) {
+	p := NewProcessor()
+
+	for _, tc := range []struct {
+		ip       string
+		cidrs    []string
+		expected bool
+	}{
+		{"10.1.2.3", []string{"10.0.0.0/8"}, true},
+		{"192.168.1.10", []string{"10.0.0.0/8", "192.168.0.0/16"}, true},
+		{"172.32.0.1", []string{"172.16.0.0/12"}, false},
+		{"2001:db8::1", []string{"2001:db8::/32"}, true},
+		{"2001:db9::1", []string{"2001:db8::/32"}, false},
+		{"::ffff:10.0.0.1", []string{"10.0.0.0/8"}, true},
+		{"not an ip", []string{"10.0.0.0/8"}, false},
+		{"", []string{"0.0.0.0/0"}, false},
+		{"10.0.0.1", []string{}, false},
+	} {
+		if got := p.IPInCIDR(tc.ip, tc.cidrs...); got != tc.expected {
+			t.Errorf("Test Fail on %s in %v, we want %v, we got %v", tc.ip, tc.cidrs, tc.expected, got)
+		}
+	}
+
+	if p.IPInCIDR("10.0.0.1", "10.0.0.0/33") || !p.IPInCIDR("10.0.0.1", "10.0.0.0/33", "10.0.0.0/8") {
+		t.Error("expected the invalid CIDR block to never contain the IP, without failing")
+	}
+}
+
+// TestIsPrivateIP checks if the private, loopback and link local IPs are identified.
+func TestIsPrivateIP(t *testing.T) {
+	p := NewProcessor()
+
+	for ip, expected := range map[string]bool{
+		"10.0.0.1":    true,
+		"172.16.5.4":  true,
+		"192.168.0.1": true,
+		"127.0.0.1":   true,
+		"169.254.1.1": true,
+		"fd00::1":     true,
+		"::1":         true,
+		"8.8.8.8":     false,
+		"200.160.2.3": false,
+		"invalid":     false,
+	} {
+		if got := p.IsPrivateIP(ip); got != expected {
+			t.Errorf("Test Fail on %s, we want %v, we got %v", ip, expected, got)
+		}
+	}
+}
+
+// TestDistance checks if the distances between the points are computed in kilometers.
+func TestDistance(t *testing.T) {
+	p := NewProcessor()
+
+	// São Paulo to Rio de Janeiro is about 361 km in a straight line
+	if got := p.Distance(-23.5505, -46.6333, -22.9068, -43.1729); math.Abs(got-361) > 2 {
+		t.Errorf("Test Fail, we want %v, we got %v", 361, got)
+	}
+	if got := p.Distance(0, 0, 0, 0); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+	if got := p.Distance("0", 0, 0, 180); math.Abs(got-math.Pi*earthRadius) > 0.001 {
+		t.Errorf("Test Fail, we want %v, we got %v", math.Pi*earthRadius, got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on a coordinate that isn't a number")
+		}
+	}()
+	p.Distance("abc", 0, 0, 0)
+}
+
+// testRegions are a square region with a hole and a region of two polygons.
+const testRegions = `{
+	"type": "FeatureCollection",
+	"features": [
+		{
+			"type": "Feature",
+			"properties": {"name": "square"},
+			"geometry": {
+				"type": "Polygon",
+				"coordinates": [
+					[[-47, -24], [-46, -24], [-46, -23], [-47, -23], [-47, -24]],
+					[[-46.6, -23.6], [-46.4, -23.6], [-46.4, -23.4], [-46.6, -23.4], [-46.6, -23.6]]
+				]
+			}
+		},
+		{
+			"type": "Feature",
+			"properties": {"name": "islands"},
+			"geometry": {
+				"type": "MultiPolygon",
+				"coordinates": [
+					[[[-47, -24], [-46.5, -24], [-46.5, -23], [-47, -23], [-47, -24]]],
+					[[[-40, -20], [-39, -20], [-39, -19], [-40, -20]]]
+				]
+			}
+		}
+	]
+}`
+
+// setupRegions makes the processor use the regions of a temporary GeoJSON file for a test.
+func setupRegions(t *testing.T, content string) {
+	path := filepath.Join(t.TempDir(), "regions.geojson")
+	err := os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.GetConfig().GeoRegionsPath = path
+	t.Cleanup(func() {
+		config.LoadConfig()
+	})
+}
+
+// TestRegions checks if the points are matched against the polygons of the regions and their holes.
+func TestRegions(t *testing.T) {
+	setupRegions(t, testRegions)
+	p := NewProcessor()
+
+	for _, tc := range []struct {
+		lat, lon float64
+		square   bool
+		regions  []interface{}
+	}{
+		{-23.8, -46.8, true, []interface{}{"square", "islands"}},
+		{-23.8, -46.2, true, []interface{}{"square"}},
+		{-23.5, -46.5, false, []interface{}{}},
+		{-19.9, -39.5, false, []interface{}{"islands"}},
+		{-19.2, -39.8, false, []interface{}{}},
+		{-22.9, -43.2, false, []interface{}{}},
+	} {
+		if got := p.InRegion(tc.lat, tc.lon, "square"); got != tc.square {
+			t.Errorf("Test Fail on %v,%v, we want %v, we got %v", tc.lat, tc.lon, tc.square, got)
+		}
+		if got := p.Regions(tc.lat, tc.lon); !reflect.DeepEqual(got, tc.regions) {
+			t.Errorf("Test Fail on %v,%v, we want %v, we got %v", tc.lat, tc.lon, tc.regions, got)
+		}
+	}
+
+	if p.InRegion(-23.8, -46.8, "unknown") {
+		t.Error("expected the region that isn't configured to never contain the point, without failing")
+	}
+}
+
+// TestInvalidRegions checks if the invalid GeoJSON files fail to load.
+func TestInvalidRegions(t *testing.T) {
+	for name, content := range map[string]string{
+		"invalid json":     `{`,
+		"no name":          `{"features": [{"properties": {}, "geometry": {"type": "Polygon", "coordinates": []}}]}`,
+		"point":            `{"features": [{"properties": {"name": "a"}, "geometry": {"type": "Point", "coordinates": [0, 0]}}]}`,
+		"invalid position": `{"features": [{"properties": {"name": "a"}, "geometry": {"type": "Polygon", "coordinates": [[[0]]]}}]}`,
+	} {
+		path := filepath.Join(t.TempDir(), "regions.geojson")
+		err := os.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = loadRegions(path)
+		if err == nil {
+			t.Errorf("expected an error on the regions with %s", name)
+		}
+	}
+}
+
+// TestSetupGeo checks if the missing or broken geographic files fail the setup, and if their failures
+// are cached.
+func TestSetupGeo(t *testing.T) {
+	setupRegions(t, testRegions)
+	if err := SetupGeo(config.GetConfig()); err != nil {
+		t.Errorf("Test Fail, we want %v, we got %v", nil, err)
+	}
+
+	for name, cfg := range map[string]*config.Config{
+		"missing regions":        {GeoRegionsPath: filepath.Join(t.TempDir(), "missing.geojson")},
+		"missing municipalities": {GeoMunicipalitiesPath: filepath.Join(t.TempDir(), "missing.csv")},
+	} {
+		if err := SetupGeo(cfg); err == nil {
+			t.Errorf("expected an error on the setup with %s", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "regions.geojson")
+	err := os.WriteFile(path, []byte(`{`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetupGeo(&config.Config{GeoRegionsPath: path}); err == nil {
+		t.Error("expected an error on the setup with broken regions")
+	}
+
+	// The file is fixed after the failure, but the failure is cached
+	err = os.WriteFile(path, []byte(testRegions), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := regionsOf(path); err == nil {
+		t.Error("expected the failure of the regions to be cached")
+	}
+}
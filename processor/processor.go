@@ -3,6 +3,9 @@ package processor
 import (
 	"encoding/json"
 	"strconv"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+	log "github.com/sirupsen/logrus"
 )
 
 // Processor its utilitary class for assertions
@@ -18,21 +21,48 @@ func (p *Processor) Boolean(value bool) string {
 	return strconv.FormatBool(value)
 }
 
-// Contains method to check if entry is into a array
-func (p *Processor) Contains(slice []interface{}, val interface{}) bool {
-	for _, item := range slice {
-		if item == val {
-			return true
-		}
+// ToMap method to convert a string to a map. It returns an empty map, logging the error, when the
+// string isn't a JSON object
+func (p *Processor) ToMap(objectstring string) map[string]interface{} {
+	o := make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(objectstring), &o)
+	if err != nil {
+		log.WithError(err).Warn("error on decode the JSON object")
+		return make(map[string]interface{})
 	}
-	return false
+
+	return o
 }
 
-// ToMap method to convert a string to a map
-func (p *Processor) ToMap(objectstring string) map[string]interface{} {
+// ParseJSON method converts a JSON object to a TypedMap, so its entries are read with the typed getters
+// and their paths, like `processor.ParseJSON(value).GetString("address.state")`. Unlike ToMap, it
+// panics when the string isn't a JSON object
+func (p *Processor) ParseJSON(objectstring string) *types.TypedMap {
 	o := make(map[string]interface{})
 
-	json.Unmarshal([]byte(objectstring), &o)
+	err := json.Unmarshal([]byte(objectstring), &o)
+	if err != nil {
+		log.Panicf("error on decode the JSON object: %v", err)
+	}
 
-	return o
+	return types.NewTypedMapFromMap(o)
+}
+
+// JSONPath method extracts the value of a path, like "$.accounts[0].balance" or "accounts[0].balance",
+// from a JSON document. It returns nil when the path doesn't exist
+func (p *Processor) JSONPath(document string, path string) interface{} {
+	var root interface{}
+	err := json.Unmarshal([]byte(document), &root)
+	if err != nil {
+		log.Panicf("error on decode the JSON document: %v", err)
+	}
+
+	if path == "" || path == "$" {
+		return root
+	}
+	if path[0] != '$' {
+		path = "$." + path
+	}
+	return types.NewTypedMapFromMap(map[string]interface{}{"$": root}).Get(path)
 }
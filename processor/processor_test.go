@@ -66,3 +66,52 @@ func TestContainsFalse(t *testing.T) {
 	}
 
 }
+
+// TestToMapInvalid checks if ToMap returns an empty map when the string isn't a JSON object.
+func TestToMapInvalid(t *testing.T) {
+	for _, input := range []string{"{invalid", "", "[1]"} {
+		if got := NewProcessor().ToMap(input); got == nil || len(got) != 0 {
+			t.Errorf("Test Fail, we want %v, we got %v", map[string]interface{}{}, got)
+		}
+	}
+}
+
+// TestParseJSONWithPanic checks if ParseJSON panics when the string isn't a JSON object.
+func TestParseJSONWithPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on an invalid JSON object")
+		}
+	}()
+	NewProcessor().ParseJSON("{invalid")
+}
+
+// TestJSONPath checks if the values of the paths are extracted from the JSON documents.
+func TestJSONPath(t *testing.T) {
+	p := NewProcessor()
+	document := `{"customer": {"accounts": [{"balance": 10.5}, {"balance": 20}]}, "tags": ["a"]}`
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"$.customer.accounts[1].balance", float64(20)},
+		{"customer.accounts[0].balance", 10.5},
+		{"tags[0]", "a"},
+		{"$.missing", nil},
+	}
+
+	for _, test := range tests {
+		if got := p.JSONPath(document, test.path); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Test Fail on %s, we want %v, we got %v", test.path, test.expected, got)
+		}
+	}
+
+	if got := p.JSONPath(`[{"id": 7}]`, "$[0].id"); got != float64(7) {
+		t.Errorf("Test Fail, we want %v, we got %v", 7, got)
+	}
+
+	if got := p.ParseJSON(document).GetInt("customer.accounts[1].balance"); got != 20 {
+		t.Errorf("Test Fail, we want %v, we got %v", 20, got)
+	}
+}
@@ -0,0 +1,192 @@
+package processor
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxRegexps is the maximum number of compiled regular expressions kept by the cache.
+const maxRegexps = 256
+
+// regexpEntry is a compiled regular expression of the cache.
+type regexpEntry struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// regexpCache is a LRU cache of the compiled regular expressions by pattern, shared by all the
+// evaluations. It's bounded, so the patterns built from the context values can't grow it forever.
+//
+// Property:
+//   - mutex: protects the entries of the cache.
+//   - size: the maximum number of entries.
+//   - entries: the elements of the LRU list by pattern.
+//   - lru: the cached entries, from the most to the least recently used.
+type regexpCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// newRegexpCache creates an empty cache with the given size
+func newRegexpCache(size int) *regexpCache {
+	return &regexpCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns the compiled regular expression of the pattern, if it's cached.
+func (rc *regexpCache) get(pattern string) (*regexp.Regexp, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	element, ok := rc.entries[pattern]
+	if !ok {
+		return nil, false
+	}
+	rc.lru.MoveToFront(element)
+	return element.Value.(*regexpEntry).re, true
+}
+
+// put stores the compiled regular expression of the pattern, evicting the least recently used entry
+// when the cache is full.
+func (rc *regexpCache) put(pattern string, re *regexp.Regexp) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if element, ok := rc.entries[pattern]; ok {
+		rc.lru.MoveToFront(element)
+		return
+	}
+
+	for rc.lru.Len() >= rc.size && rc.lru.Len() > 0 {
+		oldest := rc.lru.Back()
+		rc.lru.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*regexpEntry).pattern)
+	}
+	rc.entries[pattern] = rc.lru.PushFront(&regexpEntry{pattern: pattern, re: re})
+}
+
+// regexps are the compiled regular expressions by pattern, shared by all the evaluations.
+var regexps = newRegexpCache(maxRegexps)
+
+// compile returns the compiled regular expression of the pattern, panicking when it's invalid.
+func compile(pattern string) *regexp.Regexp {
+	if re, ok := regexps.get(pattern); ok {
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		log.Panicf("invalid regular expression %s: %v", pattern, err)
+	}
+	regexps.put(pattern, re)
+	return re
+}
+
+// Match method checks if the value matches the regular expression
+func (p *Processor) Match(pattern string, value string) bool {
+	return compile(pattern).MatchString(value)
+}
+
+// Extract method returns the first group of the first match of the regular expression on the value, or
+// the whole match when the expression has no groups. It returns an empty string when there's no match
+func (p *Processor) Extract(pattern string, value string) string {
+	match := compile(pattern).FindStringSubmatch(value)
+	switch len(match) {
+	case 0:
+		return ""
+	case 1:
+		return match[0]
+	default:
+		return match[1]
+	}
+}
+
+// ExtractAll method returns all the matches of the regular expression on the value, or the first group
+// of each one when the expression has groups
+func (p *Processor) ExtractAll(pattern string, value string) []interface{} {
+	result := []interface{}{}
+	for _, match := range compile(pattern).FindAllStringSubmatch(value, -1) {
+		if len(match) > 1 {
+			result = append(result, match[1])
+			continue
+		}
+		result = append(result, match[0])
+	}
+	return result
+}
+
+// ContainsString method checks if the value contains the substring
+func (p *Processor) ContainsString(value string, substr string) bool {
+	return strings.Contains(value, substr)
+}
+
+// ContainsIgnoreCase method checks if the value contains the substring, ignoring the case
+func (p *Processor) ContainsIgnoreCase(value string, substr string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substr))
+}
+
+// EqualsIgnoreCase method checks if the strings are equal, ignoring the case
+func (p *Processor) EqualsIgnoreCase(a string, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// StartsWith method checks if the value starts with the prefix
+func (p *Processor) StartsWith(value string, prefix string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// EndsWith method checks if the value ends with the suffix
+func (p *Processor) EndsWith(value string, suffix string) bool {
+	return strings.HasSuffix(value, suffix)
+}
+
+// Split method splits the value by the separator, trimming the spaces of the parts
+func (p *Processor) Split(value string, separator string) []interface{} {
+	result := []interface{}{}
+	if value == "" {
+		return result
+	}
+	for _, part := range strings.Split(value, separator) {
+		result = append(result, strings.TrimSpace(part))
+	}
+	return result
+}
+
+// Join method joins the items of the slice with the separator
+func (p *Processor) Join(slice []interface{}, separator string) string {
+	parts := make([]string, len(slice))
+	for i, item := range slice {
+		parts[i] = fmt.Sprintf("%v", item)
+	}
+	return strings.Join(parts, separator)
+}
+
+// Trim method removes the leading and trailing spaces of the value
+func (p *Processor) Trim(value string) string {
+	return strings.TrimSpace(value)
+}
+
+// Lower method converts the value to lower case
+func (p *Processor) Lower(value string) string {
+	return strings.ToLower(value)
+}
+
+// Upper method converts the value to upper case
+func (p *Processor) Upper(value string) string {
+	return strings.ToUpper(value)
+}
+
+// Replace method replaces all the occurrences of a string on the value by another
+func (p *Processor) Replace(value string, old string, new string) string {
+	return strings.ReplaceAll(value, old, new)
+}
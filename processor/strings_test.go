@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// TestRegexHelpers checks the matching and the extraction of regular expressions.
+func TestRegexHelpers(t *testing.T) {
+	p := NewProcessor()
+
+	if !p.Match(`^\d{5}-\d{3}$`, "70040-912") || p.Match(`^\d{5}-\d{3}$`, "70040912") {
+		t.Error("expected the CEP to match only with the hyphen")
+	}
+	if got := p.Extract(`agency (\d+)`, "agency 1234 account 99"); got != "1234" {
+		t.Errorf("Test Fail, we want %v, we got %v", "1234", got)
+	}
+	if got := p.Extract(`\d+`, "agency 1234"); got != "1234" {
+		t.Errorf("Test Fail, we want %v, we got %v", "1234", got)
+	}
+	if got := p.Extract(`\d+`, "none"); got != "" {
+		t.Errorf("Test Fail, we want %v, we got %v", "", got)
+	}
+
+	expected := []interface{}{"a1", "b2"}
+	if got := p.ExtractAll(`[a-z]\d`, "a1-b2-cc"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on an invalid regular expression")
+		}
+	}()
+	p.Match("(", "value")
+}
+
+// TestRegexpCacheBounded checks if the cache of the regular expressions keeps only the most recently
+// used patterns.
+func TestRegexpCacheBounded(t *testing.T) {
+	cache := newRegexpCache(2)
+	for _, pattern := range []string{"a", "b", "c"} {
+		cache.put(pattern, regexp.MustCompile(pattern))
+		if pattern == "b" {
+			cache.get("a")
+		}
+	}
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected the least recently used pattern to be evicted")
+	}
+	for _, pattern := range []string{"a", "c"} {
+		if re, ok := cache.get(pattern); !ok || re.String() != pattern {
+			t.Errorf("Test Fail, we want %v, we got %v", pattern, re)
+		}
+	}
+	if cache.lru.Len() != 2 || len(cache.entries) != 2 {
+		t.Errorf("expected 2 cached patterns, got %d", cache.lru.Len())
+	}
+}
+
+// TestStringHelpers checks the string helpers.
+func TestStringHelpers(t *testing.T) {
+	p := NewProcessor()
+
+	checks := map[string]bool{
+		"ContainsString":     p.ContainsString("Banco do Brasil", "do"),
+		"ContainsIgnoreCase": p.ContainsIgnoreCase("Banco do Brasil", "BRASIL"),
+		"EqualsIgnoreCase":   p.EqualsIgnoreCase("gold", "GOLD"),
+		"StartsWith":         p.StartsWith("+5561999", "+55"),
+		"EndsWith":           p.EndsWith("user@bb.com.br", ".com.br"),
+		"not ContainsString": !p.ContainsString("Banco do Brasil", "brasil"),
+	}
+	for name, ok := range checks {
+		if !ok {
+			t.Errorf("Test Fail on %s", name)
+		}
+	}
+
+	expected := []interface{}{"gold", "black", "platinum"}
+	if got := p.Split("gold, black ,platinum", ","); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+	if got := p.Split("", ","); len(got) != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, len(got))
+	}
+	if got := p.Join([]interface{}{"a", 1, true}, "|"); got != "a|1|true" {
+		t.Errorf("Test Fail, we want %v, we got %v", "a|1|true", got)
+	}
+	if got := p.Upper(p.Trim("  sp ")) + p.Lower("DF") + p.Replace("a-b-c", "-", ""); got != "SPdfabc" {
+		t.Errorf("Test Fail, we want %v, we got %v", "SPdfabc", got)
+	}
+}
@@ -0,0 +1,208 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// Clock returns the current time used by the rulesheets. The tests can replace it to fix the current
+// time.
+var Clock = time.Now
+
+// Now method returns the current time on the time zone of `FEATWS_RULLER_TIMEZONE`
+func (p *Processor) Now() time.Time {
+	return Clock().In(config.GetConfig().TimeLocation())
+}
+
+// Today method returns the start of the current day on the time zone of `FEATWS_RULLER_TIMEZONE`
+func (p *Processor) Today() time.Time {
+	return types.StartOfDay(p.Now())
+}
+
+// Date method creates the date of the year, month and day on the time zone of `FEATWS_RULLER_TIMEZONE`
+func (p *Processor) Date(year int64, month int64, day int64) time.Time {
+	return time.Date(int(year), time.Month(month), int(day), 0, 0, 0, 0, config.GetConfig().TimeLocation())
+}
+
+// ParseTime method parses a date string with the configured date layouts, RFC3339 or "2006-01-02"
+func (p *Processor) ParseTime(value string) time.Time {
+	parsed, err := types.ParseTime(value)
+	if err != nil {
+		log.Panic(err.Error())
+	}
+	return parsed
+}
+
+// InTimeZone method converts the time to a time zone, like "America/Sao_Paulo"
+func (p *Processor) InTimeZone(t time.Time, zone string) time.Time {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		log.Panicf("invalid time zone %s: %v", zone, err)
+	}
+	return t.In(location)
+}
+
+// DaysBetween method returns the number of calendar days from a date to another, negative when the
+// second date is before the first one
+func (p *Processor) DaysBetween(from time.Time, to time.Time) int64 {
+	to = to.In(from.Location())
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(toDay.Sub(fromDay).Hours() / 24)
+}
+
+// MonthsBetween method returns the number of full months from a date to another, negative when the
+// second date is before the first one
+func (p *Processor) MonthsBetween(from time.Time, to time.Time) int64 {
+	to = to.In(from.Location())
+	if to.Before(from) {
+		return -p.MonthsBetween(to, from)
+	}
+
+	months := int64(to.Year()-from.Year())*12 + int64(to.Month()-from.Month())
+	if to.Day() < from.Day() && to.Day() != daysIn(to.Year(), to.Month()) {
+		months--
+	}
+	return months
+}
+
+// YearsBetween method returns the number of full years from a date to another, negative when the
+// second date is before the first one
+func (p *Processor) YearsBetween(from time.Time, to time.Time) int64 {
+	return p.MonthsBetween(from, to) / 12
+}
+
+// Age method returns the number of full years since the date, like the age of a birth date
+func (p *Processor) Age(birth time.Time) int64 {
+	return p.YearsBetween(birth, p.Now())
+}
+
+// DaysSince method returns the number of calendar days since the date
+func (p *Processor) DaysSince(t time.Time) int64 {
+	return p.DaysBetween(t, p.Now())
+}
+
+// AddDays method adds a number of days to the time
+func (p *Processor) AddDays(t time.Time, days int64) time.Time {
+	return t.AddDate(0, 0, int(days))
+}
+
+// AddMonths method adds a number of months to the time. The day is limited to the last day of the
+// resulting month, so January 31 plus a month is the last day of February
+func (p *Processor) AddMonths(t time.Time, months int64) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	first = first.AddDate(0, int(months), 0)
+
+	day := t.Day()
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// AddYears method adds a number of years to the time, limiting February 29 to February 28
+func (p *Processor) AddYears(t time.Time, years int64) time.Time {
+	return p.AddMonths(t, years*12)
+}
+
+// Weekday method returns the day of the week of the time, from 0 (Sunday) to 6 (Saturday)
+func (p *Processor) Weekday(t time.Time) int64 {
+	return int64(t.Weekday())
+}
+
+// Hour method returns the hour of the time, from 0 to 23
+func (p *Processor) Hour(t time.Time) int64 {
+	return int64(t.Hour())
+}
+
+// IsWeekend method checks if the time is on a Saturday or a Sunday
+func (p *Processor) IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// IsBusinessDay method checks if the time isn't on a weekend nor on a holiday of `FEATWS_RULLER_HOLIDAYS`
+func (p *Processor) IsBusinessDay(t time.Time) bool {
+	return !p.IsWeekend(t) && !config.GetConfig().Holidays[t.Format("2006-01-02")]
+}
+
+// AddBusinessDays method adds a number of business days to the time, skipping the weekends and the
+// holidays
+func (p *Processor) AddBusinessDays(t time.Time, days int64) time.Time {
+	step := 1
+	if days < 0 {
+		step = -1
+		days = -days
+	}
+
+	for days > 0 {
+		t = t.AddDate(0, 0, step)
+		if p.IsBusinessDay(t) {
+			days--
+		}
+	}
+	return t
+}
+
+// BusinessDaysBetween method returns the number of business days after a date up to another, negative
+// when the second date is before the first one. The whole weeks are counted without iterating them, and
+// a zero date, like a missing one, fails the evaluation
+func (p *Processor) BusinessDaysBetween(from time.Time, to time.Time) int64 {
+	if from.IsZero() || to.IsZero() {
+		log.Panic("the dates of the business days can't be zero")
+	}
+
+	days := p.DaysBetween(from, to)
+	if days < 0 {
+		return -p.businessDaysAfter(to.AddDate(0, 0, -1), -days)
+	}
+	return p.businessDaysAfter(from, days)
+}
+
+// businessDaysAfter returns the number of business days on the given number of days after a date. Each
+// whole week has 5 weekdays, so only the remaining days are checked, and the holidays on a weekday of the
+// interval are subtracted.
+func (p *Processor) businessDaysAfter(from time.Time, days int64) int64 {
+	count := days / 7 * 5
+	for i := days / 7 * 7; i < days; i++ {
+		if !p.IsWeekend(from.AddDate(0, 0, int(i+1))) {
+			count++
+		}
+	}
+
+	// The days formatted as "2006-01-02" are in the same order as the dates
+	first := from.AddDate(0, 0, 1).Format("2006-01-02")
+	last := from.AddDate(0, 0, int(days)).Format("2006-01-02")
+	for holiday, ok := range config.GetConfig().Holidays {
+		if !ok || holiday < first || holiday > last {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", holiday)
+		if err == nil && !p.IsWeekend(date) {
+			count--
+		}
+	}
+	return count
+}
+
+// Before method checks if a time is before another
+func (p *Processor) Before(t time.Time, other time.Time) bool {
+	return t.Before(other)
+}
+
+// After method checks if a time is after another
+func (p *Processor) After(t time.Time, other time.Time) bool {
+	return t.After(other)
+}
+
+// Between method checks if a time is between two others, inclusive
+func (p *Processor) Between(t time.Time, from time.Time, to time.Time) bool {
+	return !t.Before(from) && !t.After(to)
+}
+
+// daysIn returns the number of days of the month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,169 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// fixClock fixes the current time of the processor during the test.
+func fixClock(t *testing.T, now time.Time) {
+	Clock = func() time.Time { return now }
+	t.Cleanup(func() {
+		Clock = time.Now
+	})
+}
+
+// date creates a date on UTC.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TestNowWithClock checks if the current time comes from the clock.
+func TestNowWithClock(t *testing.T) {
+	fixClock(t, time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC))
+	p := NewProcessor()
+
+	if got := p.Today(); !got.Equal(date(2024, 3, 10)) {
+		t.Errorf("Test Fail, we want %v, we got %v", date(2024, 3, 10), got)
+	}
+	if got := p.Age(date(2000, 3, 11)); got != 23 {
+		t.Errorf("Test Fail, we want %v, we got %v", 23, got)
+	}
+	if got := p.Age(date(2000, 3, 10)); got != 24 {
+		t.Errorf("Test Fail, we want %v, we got %v", 24, got)
+	}
+	if got := p.DaysSince(date(2023, 12, 11)); got != 90 {
+		t.Errorf("Test Fail, we want %v, we got %v", 90, got)
+	}
+}
+
+// TestDiffs checks the number of days, months and years between dates.
+func TestDiffs(t *testing.T) {
+	p := NewProcessor()
+
+	tests := []struct {
+		got      int64
+		expected int64
+	}{
+		{p.DaysBetween(date(2024, 2, 28), date(2024, 3, 1)), 2},
+		{p.DaysBetween(date(2024, 3, 1), date(2024, 2, 28)), -2},
+		{p.MonthsBetween(date(2024, 1, 15), date(2024, 3, 14)), 1},
+		{p.MonthsBetween(date(2024, 1, 15), date(2024, 3, 15)), 2},
+		{p.MonthsBetween(date(2024, 1, 31), date(2024, 2, 29)), 1},
+		{p.MonthsBetween(date(2024, 3, 15), date(2024, 1, 15)), -2},
+		{p.YearsBetween(date(2000, 6, 1), date(2024, 5, 31)), 23},
+		{p.YearsBetween(date(2000, 6, 1), date(2024, 6, 1)), 24},
+	}
+
+	for i, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+}
+
+// TestAddMonths checks if the day is limited to the last day of the resulting month.
+func TestAddMonths(t *testing.T) {
+	p := NewProcessor()
+
+	tests := []struct {
+		got      time.Time
+		expected time.Time
+	}{
+		{p.AddMonths(date(2024, 1, 31), 1), date(2024, 2, 29)},
+		{p.AddMonths(date(2024, 3, 31), -1), date(2024, 2, 29)},
+		{p.AddMonths(date(2024, 1, 15), 13), date(2025, 2, 15)},
+		{p.AddYears(date(2024, 2, 29), 1), date(2025, 2, 28)},
+		{p.AddDays(date(2024, 2, 28), 2), date(2024, 3, 1)},
+	}
+
+	for i, test := range tests {
+		if !test.got.Equal(test.expected) {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+}
+
+// TestInTimeZone checks if the time is converted to the time zone.
+func TestInTimeZone(t *testing.T) {
+	p := NewProcessor()
+	got := p.InTimeZone(time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC), "America/Sao_Paulo")
+
+	if p.Hour(got) != 9 || p.Weekday(got) != 1 {
+		t.Errorf("Test Fail, we want %v, we got %v", "monday 9h", got)
+	}
+}
+
+// TestBusinessDays checks if the weekends and the holidays are skipped by the business day functions.
+func TestBusinessDays(t *testing.T) {
+	config.GetConfig().Holidays = map[string]bool{"2024-11-15": true}
+	defer func() {
+		config.GetConfig().Holidays = nil
+	}()
+
+	p := NewProcessor()
+
+	// 2024-11-14 is a Thursday, 2024-11-15 a holiday and 2024-11-16 a Saturday
+	if p.IsBusinessDay(date(2024, 11, 15)) || p.IsBusinessDay(date(2024, 11, 16)) || !p.IsBusinessDay(date(2024, 11, 18)) {
+		t.Error("expected the holiday and the weekend to not be business days")
+	}
+	if got := p.AddBusinessDays(date(2024, 11, 14), 1); !got.Equal(date(2024, 11, 18)) {
+		t.Errorf("Test Fail, we want %v, we got %v", date(2024, 11, 18), got)
+	}
+	if got := p.AddBusinessDays(date(2024, 11, 18), -1); !got.Equal(date(2024, 11, 14)) {
+		t.Errorf("Test Fail, we want %v, we got %v", date(2024, 11, 14), got)
+	}
+	if got := p.BusinessDaysBetween(date(2024, 11, 14), date(2024, 11, 20)); got != 3 {
+		t.Errorf("Test Fail, we want %v, we got %v", 3, got)
+	}
+	if got := p.BusinessDaysBetween(date(2024, 11, 20), date(2024, 11, 14)); got != -3 {
+		t.Errorf("Test Fail, we want %v, we got %v", -3, got)
+	}
+}
+
+// TestBusinessDaysBetweenWeeks checks if the business days of the long intervals, counted by whole
+// weeks, are the same of counting each day.
+func TestBusinessDaysBetweenWeeks(t *testing.T) {
+	config.GetConfig().Holidays = map[string]bool{"2024-11-15": true, "2024-11-16": true, "2024-12-25": true, "2025-01-01": true}
+	defer func() {
+		config.GetConfig().Holidays = nil
+	}()
+
+	p := NewProcessor()
+	start := date(2024, 11, 1)
+	for offset := 0; offset < 7; offset++ {
+		from := start.AddDate(0, 0, offset)
+		for days := -70; days <= 70; days++ {
+			to := from.AddDate(0, 0, days)
+
+			expected := int64(0)
+			for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+				if p.IsBusinessDay(d.AddDate(0, 0, 1)) {
+					expected++
+				}
+			}
+			for d := to; d.Before(from); d = d.AddDate(0, 0, 1) {
+				if p.IsBusinessDay(d) {
+					expected--
+				}
+			}
+
+			if got := p.BusinessDaysBetween(from, to); got != expected {
+				t.Errorf("Test Fail from %v to %v, we want %v, we got %v", from, to, expected, got)
+			}
+		}
+	}
+}
+
+// TestBusinessDaysBetweenZero checks if a zero date fails instead of counting the business days since
+// the year 1.
+func TestBusinessDaysBetweenZero(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on a zero date")
+		}
+	}()
+	NewProcessor().BusinessDaysBetween(time.Time{}, date(2024, 11, 14))
+}
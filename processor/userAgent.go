@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// platforms are the platforms detected on the user agents, in the order they're checked, since the iOS
+// and Android user agents also mention "Mac OS X" and "Linux".
+//
+// Property:
+//   - name: the name of the platform returned by the helpers.
+//   - detect: the pattern that identifies the platform.
+//   - version: the pattern of the version of the platform, with the "_" separators of iOS and macOS.
+var platforms = []struct {
+	name    string
+	detect  *regexp.Regexp
+	version *regexp.Regexp
+}{
+	{"ios", regexp.MustCompile(`(?i)\b(?:iphone|ipad|ipod|ios|cfnetwork)\b`), regexp.MustCompile(`(?i)\bi?os[ /]([0-9]+(?:[_.][0-9]+)*)`)},
+	{"android", regexp.MustCompile(`(?i)\bandroid\b`), regexp.MustCompile(`(?i)\bandroid[ /]([0-9]+(?:\.[0-9]+)*)`)},
+	{"windows", regexp.MustCompile(`(?i)\bwindows\b`), regexp.MustCompile(`(?i)\bwindows nt ([0-9]+(?:\.[0-9]+)*)`)},
+	{"macos", regexp.MustCompile(`(?i)\b(?:macintosh|mac ?os)\b`), regexp.MustCompile(`(?i)\bmac ?os ?x? ([0-9]+(?:[_.][0-9]+)*)`)},
+	{"linux", regexp.MustCompile(`(?i)\blinux\b`), nil},
+}
+
+// parseUserAgent returns the platform and its version on the user agent, or "unknown" and an empty
+// version when there isn't a known platform.
+func parseUserAgent(userAgent string) (string, string) {
+	for _, platform := range platforms {
+		if !platform.detect.MatchString(userAgent) {
+			continue
+		}
+
+		version := ""
+		if platform.version != nil {
+			if matches := platform.version.FindStringSubmatch(userAgent); matches != nil {
+				version = strings.ReplaceAll(matches[1], "_", ".")
+			}
+		}
+		return platform.name, version
+	}
+	return "unknown", ""
+}
+
+// Platform method returns the platform of the user agent: "ios", "android", "windows", "macos", "linux"
+// or "unknown"
+func (p *Processor) Platform(userAgent string) string {
+	platform, _ := parseUserAgent(userAgent)
+	return platform
+}
+
+// OSVersion method returns the version of the platform of the user agent, like "13" on "Android 13"
+// or "16.4" on "iPhone OS 16_4", or an empty string when it isn't informed
+func (p *Processor) OSVersion(userAgent string) string {
+	_, version := parseUserAgent(userAgent)
+	return version
+}
+
+// IsMobile method checks if the user agent is of a mobile device
+func (p *Processor) IsMobile(userAgent string) bool {
+	platform, _ := parseUserAgent(userAgent)
+	return platform == "ios" || platform == "android" || strings.Contains(strings.ToLower(userAgent), "mobile")
+}
+
+// AppVersion method returns the version of the app on the user agent, from the product token with its
+// name, like "5.12.0" on "BBApp/5.12.0 (Android 13)" with the app "BBApp", or an empty string when the
+// app isn't on the user agent. The app name is case insensitive
+func (p *Processor) AppVersion(userAgent string, app string) string {
+	matches := compile(`(?i)(?:^|[\s;(])` + regexp.QuoteMeta(app) + `/([0-9A-Za-z.+-]+)`).FindStringSubmatch(userAgent)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
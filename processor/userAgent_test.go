@@ -0,0 +1,57 @@
+package processor
+
+import "testing"
+
+// TestParseUserAgent checks if the platforms and their versions are parsed from the user agents.
+func TestParseUserAgent(t *testing.T) {
+	p := NewProcessor()
+
+	for _, tc := range []struct {
+		userAgent, platform, version string
+		mobile                       bool
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148", "ios", "16.4", true},
+		{"Mozilla/5.0 (iPad; CPU OS 15_7_1 like Mac OS X) AppleWebKit/605.1.15", "ios", "15.7.1", true},
+		{"BBApp/5.12.0 (iOS 17.1; iPhone14,2)", "ios", "17.1", true},
+		{"BBApp/5.12.0 CFNetwork/1410.0.3 Darwin/22.6.0", "ios", "", true},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 Chrome/116.0 Mobile Safari/537.36", "android", "13", true},
+		{"BBApp/5.9.1 (Android 10.0.1; Moto G)", "android", "10.0.1", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/116.0 Safari/537.36", "windows", "10.0", false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 Version/16.5 Safari/605.1.15", "macos", "10.15.7", false},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/117.0", "linux", "", false},
+		{"curl/8.1.2", "unknown", "", false},
+		{"", "unknown", "", false},
+	} {
+		if got := p.Platform(tc.userAgent); got != tc.platform {
+			t.Errorf("Test Fail on %q, we want %v, we got %v", tc.userAgent, tc.platform, got)
+		}
+		if got := p.OSVersion(tc.userAgent); got != tc.version {
+			t.Errorf("Test Fail on %q, we want %v, we got %v", tc.userAgent, tc.version, got)
+		}
+		if got := p.IsMobile(tc.userAgent); got != tc.mobile {
+			t.Errorf("Test Fail on %q, we want %v, we got %v", tc.userAgent, tc.mobile, got)
+		}
+	}
+}
+
+// TestAppVersion checks if the version of the app is extracted from its product token.
+func TestAppVersion(t *testing.T) {
+	p := NewProcessor()
+
+	for _, tc := range []struct {
+		userAgent, app, expected string
+	}{
+		{"BBApp/5.12.0 (Android 13; SM-S911B)", "BBApp", "5.12.0"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_4 like Mac OS X) bbapp/5.13.0-beta.2", "BBApp", "5.13.0-beta.2"},
+		{"Mozilla/5.0 (Linux; Android 13) OtherBBApp/1.0", "BBApp", ""},
+		{"Mozilla/5.0 (Linux; Android 13)", "BBApp", ""},
+	} {
+		if got := p.AppVersion(tc.userAgent, tc.app); got != tc.expected {
+			t.Errorf("Test Fail on %q, we want %v, we got %v", tc.userAgent, tc.expected, got)
+		}
+	}
+
+	if !p.VersionInRange(p.AppVersion("BBApp/5.12.3 (Android 13)", "BBApp"), "^5.12") {
+		t.Error("expected the app version to be usable on the version ranges")
+	}
+}
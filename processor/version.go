@@ -0,0 +1,315 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// version is a parsed semantic or dotted numeric version, like "5.12.0", "v2.1.0-beta.1" or "10.4".
+//
+// Property:
+//   - numbers: the numeric components of the version. The missing components compare as zero.
+//   - prerelease: the dot separated identifiers of the pre-release, like ["beta", "1"].
+type version struct {
+	numbers    []int64
+	prerelease []string
+}
+
+// parseVersion parses a version, ignoring a "v" prefix and the build metadata after "+".
+func parseVersion(str string) (*version, error) {
+	value := strings.TrimSpace(str)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "v"), "V")
+	if i := strings.Index(value, "+"); i >= 0 {
+		value = value[:i]
+	}
+
+	v := &version{}
+	if i := strings.Index(value, "-"); i >= 0 {
+		v.prerelease = strings.Split(value[i+1:], ".")
+		value = value[:i]
+		for _, identifier := range v.prerelease {
+			if identifier == "" {
+				return nil, fmt.Errorf("invalid version %s", str)
+			}
+		}
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("invalid version %s", str)
+	}
+	for _, component := range strings.Split(value, ".") {
+		number, err := strconv.ParseInt(component, 10, 64)
+		if err != nil || number < 0 || strings.HasPrefix(component, "+") {
+			return nil, fmt.Errorf("invalid version %s", str)
+		}
+		v.numbers = append(v.numbers, number)
+	}
+	return v, nil
+}
+
+// mustParseVersion parses a version, panicking when it's invalid.
+func mustParseVersion(str string) *version {
+	v, err := parseVersion(str)
+	if err != nil {
+		log.Panic(err.Error())
+	}
+	return v
+}
+
+// number returns the numeric component of the index, or zero when it's missing.
+func (v *version) number(index int) int64 {
+	if index < len(v.numbers) {
+		return v.numbers[index]
+	}
+	return 0
+}
+
+// compare compares the versions by the semantic versioning precedence: the numeric components, and
+// then a version with a pre-release is less than the same version without it.
+func (v *version) compare(other *version) int {
+	size := len(v.numbers)
+	if len(other.numbers) > size {
+		size = len(other.numbers)
+	}
+	for i := 0; i < size; i++ {
+		if a, b := v.number(i), other.number(i); a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if cmp := compareIdentifiers(v.prerelease[i], other.prerelease[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return compareInts(int64(len(v.prerelease)), int64(len(other.prerelease)))
+}
+
+// compareIdentifiers compares pre-release identifiers: the numeric ones by value and before the
+// alphanumeric ones, compared lexically.
+func compareIdentifiers(a string, b string) int {
+	na, errA := strconv.ParseInt(a, 10, 64)
+	nb, errB := strconv.ParseInt(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInts compares two ints.
+func compareInts(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IsVersion method checks if the value is a valid semantic or dotted numeric version
+func (p *Processor) IsVersion(value string) bool {
+	_, err := parseVersion(value)
+	return err == nil
+}
+
+// CompareVersions method compares the versions, returning -1, 0 or +1 when the first one is less than,
+// equal to or greater than the second one. The missing components are zero, so "5.12" equals "5.12.0",
+// and a pre-release, like "5.12.0-beta.1", is less than its release
+func (p *Processor) CompareVersions(a string, b string) int64 {
+	return int64(mustParseVersion(a).compare(mustParseVersion(b)))
+}
+
+// VersionEqual method checks if the versions are equal
+func (p *Processor) VersionEqual(a string, b string) bool {
+	return p.CompareVersions(a, b) == 0
+}
+
+// VersionLess method checks if the first version is less than the second one
+func (p *Processor) VersionLess(a string, b string) bool {
+	return p.CompareVersions(a, b) < 0
+}
+
+// VersionGreater method checks if the first version is greater than the second one
+func (p *Processor) VersionGreater(a string, b string) bool {
+	return p.CompareVersions(a, b) > 0
+}
+
+// VersionAtLeast method checks if the version is greater than or equal to the minimum one, like
+// `processor.VersionAtLeast(ctx.GetString("appVersion"), "5.12.0")`
+func (p *Processor) VersionAtLeast(value string, minimum string) bool {
+	return p.CompareVersions(value, minimum) >= 0
+}
+
+// MajorVersion method returns the first component of the version
+func (p *Processor) MajorVersion(value string) int64 {
+	return mustParseVersion(value).number(0)
+}
+
+// MinorVersion method returns the second component of the version
+func (p *Processor) MinorVersion(value string) int64 {
+	return mustParseVersion(value).number(1)
+}
+
+// PatchVersion method returns the third component of the version
+func (p *Processor) PatchVersion(value string) int64 {
+	return mustParseVersion(value).number(2)
+}
+
+// VersionInRange method checks if the version matches the range. The range has comparators separated
+// by spaces, that must all match, and alternatives separated by "||". The comparators are a version
+// with an operator (>=, <=, >, < or =), a caret range (^5.12 is >=5.12.0 <6.0.0), a tilde range
+// (~5.12 is >=5.12.0 <5.13.0), an x-range (5.x or 5.12.*) or a partial version, like 5.12, that matches
+// all the versions with its components. It panics when the version or the range are invalid
+func (p *Processor) VersionInRange(value string, versionRange string) bool {
+	v := mustParseVersion(value)
+
+	result := false
+	for _, alternative := range strings.Split(versionRange, "||") {
+		comparators := strings.Fields(alternative)
+		if len(comparators) == 0 {
+			log.Panicf("invalid version range %s", versionRange)
+		}
+
+		matched := true
+		for _, comparator := range comparators {
+			ok, err := matchComparator(v, comparator)
+			if err != nil {
+				log.Panicf("invalid version range %s: %v", versionRange, err)
+			}
+			if !ok {
+				matched = false
+			}
+		}
+		result = result || matched
+	}
+	return result
+}
+
+// matchComparator checks if the version matches a comparator of a range.
+func matchComparator(v *version, comparator string) (bool, error) {
+	for _, operator := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(comparator, operator) {
+			bound, err := parseVersion(comparator[len(operator):])
+			if err != nil {
+				return false, err
+			}
+			cmp := v.compare(bound)
+			switch operator {
+			case ">=":
+				return cmp >= 0, nil
+			case "<=":
+				return cmp <= 0, nil
+			case ">":
+				return cmp > 0, nil
+			case "<":
+				return cmp < 0, nil
+			default:
+				return cmp == 0, nil
+			}
+		}
+	}
+
+	var lower, upper *version
+	var err error
+	switch {
+	case strings.HasPrefix(comparator, "^"):
+		lower, upper, err = caretRange(comparator[1:])
+	case strings.HasPrefix(comparator, "~"):
+		lower, upper, err = tildeRange(comparator[1:])
+	default:
+		lower, upper, err = partialRange(comparator)
+		if err == nil && upper == nil && len(lower.numbers) > 0 {
+			return v.compare(lower) == 0, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return v.compare(lower) >= 0 && (upper == nil || v.compare(upper) < 0), nil
+}
+
+// caretRange returns the bounds of a caret range, that allows the changes that don't modify the first
+// non-zero component of the version.
+func caretRange(str string) (*version, *version, error) {
+	lower, err := parseVersion(str)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := 0
+	for index < len(lower.numbers)-1 && lower.numbers[index] == 0 {
+		index++
+	}
+	return lower, increment(lower, index), nil
+}
+
+// tildeRange returns the bounds of a tilde range, that allows the patch changes when the minor version
+// is informed, and the minor changes otherwise.
+func tildeRange(str string) (*version, *version, error) {
+	lower, err := parseVersion(str)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := 1
+	if len(lower.numbers) < 2 {
+		index = 0
+	}
+	return lower, increment(lower, index), nil
+}
+
+// partialRange returns the bounds of an x-range, like "5.x" or "5.12.*", or a partial version, like
+// "5.12". A full version, like "5.12.0", has no upper bound and is matched exactly by the caller.
+func partialRange(str string) (*version, *version, error) {
+	components := strings.Split(strings.TrimPrefix(strings.TrimPrefix(str, "v"), "V"), ".")
+	fixed := []string{}
+	for _, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			break
+		}
+		fixed = append(fixed, component)
+	}
+
+	if len(fixed) == 0 {
+		return &version{}, nil, nil
+	}
+
+	lower, err := parseVersion(strings.Join(fixed, "."))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fixed) == len(components) && (len(fixed) >= 3 || len(lower.prerelease) > 0) {
+		return lower, nil, nil
+	}
+	return lower, increment(lower, len(fixed)-1), nil
+}
+
+// increment returns the version with the component of the index incremented and the following ones
+// dropped, the exclusive upper bound of the ranges.
+func increment(v *version, index int) *version {
+	numbers := make([]int64, index+1)
+	copy(numbers, v.numbers)
+	numbers[index]++
+	return &version{numbers: numbers, prerelease: []string{"0"}}
+}
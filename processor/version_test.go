@@ -0,0 +1,134 @@
+package processor
+
+import "testing"
+
+// TestCompareVersions checks if the versions are compared by the semantic versioning precedence.
+func TestCompareVersions(t *testing.T) {
+	p := NewProcessor()
+
+	for _, tc := range []struct {
+		a, b     string
+		expected int64
+	}{
+		{"5.12.0", "5.12.0", 0},
+		{"5.12", "5.12.0", 0},
+		{"v5.12.0", "5.12.0", 0},
+		{"5.12.0+build.7", "5.12.0", 0},
+		{"5.9.0", "5.12.0", -1},
+		{"5.12.1", "5.12.0", 1},
+		{"10.4", "9.10.1", 1},
+		{"1.2.3.4", "1.2.3", 1},
+		{"5.12.0-beta.1", "5.12.0", -1},
+		{"5.12.0-alpha", "5.12.0-beta", -1},
+		{"5.12.0-beta.2", "5.12.0-beta.11", -1},
+		{"5.12.0-beta", "5.12.0-beta.1", -1},
+		{"5.12.0-1", "5.12.0-alpha", -1},
+		{"5.12.0-rc.1", "5.11.9", 1},
+	} {
+		if got := p.CompareVersions(tc.a, tc.b); got != tc.expected {
+			t.Errorf("Test Fail on %s and %s, we want %v, we got %v", tc.a, tc.b, tc.expected, got)
+		}
+		if got := p.CompareVersions(tc.b, tc.a); got != -tc.expected {
+			t.Errorf("Test Fail on %s and %s, we want %v, we got %v", tc.b, tc.a, -tc.expected, got)
+		}
+	}
+
+	if !p.VersionAtLeast("5.12.0", "5.12") || p.VersionAtLeast("5.12.0-beta", "5.12.0") {
+		t.Error("expected VersionAtLeast to include the minimum and exclude its pre-releases")
+	}
+	if !p.VersionLess("5.9", "5.10") || !p.VersionGreater("5.10", "5.9") || !p.VersionEqual("5", "5.0.0") {
+		t.Error("expected the versions to be compared numerically")
+	}
+}
+
+// TestVersionComponents checks if the components of the versions are returned.
+func TestVersionComponents(t *testing.T) {
+	p := NewProcessor()
+
+	if got := p.MajorVersion("v5.12.3-beta"); got != 5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 5, got)
+	}
+	if got := p.MinorVersion("5.12.3"); got != 12 {
+		t.Errorf("Test Fail, we want %v, we got %v", 12, got)
+	}
+	if got := p.PatchVersion("5.12"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+}
+
+// TestVersionInRange checks if the versions are matched against the ranges.
+func TestVersionInRange(t *testing.T) {
+	p := NewProcessor()
+
+	for _, tc := range []struct {
+		version, versionRange string
+		expected              bool
+	}{
+		{"5.12.0", "^5.12", true},
+		{"5.99.1", "^5.12", true},
+		{"5.11.9", "^5.12", false},
+		{"6.0.0", "^5.12", false},
+		{"6.0.0-beta", "^5.12", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"5.12.9", "~5.12", true},
+		{"5.13.0", "~5.12", false},
+		{"5.9.0", "~5", true},
+		{"5.0.0", ">=5.0 <6.0", true},
+		{"5.9.9", ">=5.0 <6.0", true},
+		{"6.0.0", ">=5.0 <6.0", false},
+		{"4.9.9", ">=5.0 <6.0", false},
+		{"4.2.0", "<4.0 || >=4.2", true},
+		{"4.1.0", "<4.0 || >=4.2", false},
+		{"5.12.4", "5.12.x", true},
+		{"5.13.0", "5.x", true},
+		{"6.0.0", "5.*", false},
+		{"5.12.4", "5.12", true},
+		{"5.12.4", "5.12.4", true},
+		{"5.12.5", "5.12.4", false},
+		{"5.12.4", "=5.12.4", true},
+		{"5.12.4-beta", "5.12.4-beta", true},
+		{"1.0.0", "*", true},
+		{"5.12.0", ">5.12.0", false},
+		{"5.12.0", "<=5.12", true},
+	} {
+		if got := p.VersionInRange(tc.version, tc.versionRange); got != tc.expected {
+			t.Errorf("Test Fail on %s in %s, we want %v, we got %v", tc.version, tc.versionRange, tc.expected, got)
+		}
+	}
+}
+
+// TestMalformedVersions checks if the malformed versions are invalid and panic on the comparisons.
+func TestMalformedVersions(t *testing.T) {
+	p := NewProcessor()
+
+	for _, value := range []string{"", "v", "abc", "5..1", "5.12.x", "5.-1", "5.12.0-", "5.12.0-beta..1", "5.+1"} {
+		if p.IsVersion(value) {
+			t.Errorf("expected %q to be an invalid version", value)
+		}
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic comparing %q", value)
+				}
+			}()
+			p.CompareVersions(value, "1.0.0")
+		}()
+	}
+
+	for _, versionRange := range []string{"", "^abc", ">=5.0 <x", "5.0 ||"} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic on the range %q", versionRange)
+				}
+			}()
+			p.VersionInRange("5.0.0", versionRange)
+		}()
+	}
+
+	if !p.IsVersion("v5.12.0-rc.1+build.5") {
+		t.Error("expected a full semantic version to be valid")
+	}
+}
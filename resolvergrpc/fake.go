@@ -0,0 +1,55 @@
+package resolvergrpc
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// ResolverFunc adapts a function to a ResolverBridgeServer.
+type ResolverFunc func(ctx context.Context, req *ResolveRequest) (*ResolveResponse, error)
+
+// Resolve calls the function
+func (f ResolverFunc) Resolve(ctx context.Context, req *ResolveRequest) (*ResolveResponse, error) {
+	return f(ctx, req)
+}
+
+// FakeServer is an in-process resolver bridge, served on an in-memory listener, to test the gRPC
+// clients without the network.
+//
+// Property:
+//   - listener: the in-memory listener of the server.
+//   - server: the gRPC server.
+type FakeServer struct {
+	listener *bufconn.Listener
+	server   *grpc.Server
+}
+
+// NewFakeServer starts an in-process resolver bridge answering with the server.
+func NewFakeServer(srv ResolverBridgeServer) *FakeServer {
+	fake := &FakeServer{
+		listener: bufconn.Listen(1024 * 1024),
+		server:   NewServer(srv),
+	}
+	go fake.server.Serve(fake.listener)
+	return fake
+}
+
+// DialOptions returns the options to connect to the fake server, whatever the target is.
+func (f *FakeServer) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return f.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// Close stops the fake server
+func (f *FakeServer) Close() {
+	f.server.Stop()
+	f.listener.Close()
+}
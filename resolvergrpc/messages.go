@@ -0,0 +1,174 @@
+package resolvergrpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Message is a message of the resolver bridge service that encodes itself on the protobuf wire format.
+// The messages are encoded by hand, following resolver.proto, so the build doesn't depend on protoc.
+type Message interface {
+	Marshal() ([]byte, error)
+	Unmarshal(data []byte) error
+}
+
+// ResolveRequest mirrors the ResolveRequest message of resolver.proto.
+//
+// Property:
+//   - Resolver: the name of the resolver.
+//   - Context: the context entries sent to the resolver. The values must be the ones of a decoded JSON.
+//   - Load: the params to be loaded.
+type ResolveRequest struct {
+	Resolver string
+	Context  map[string]interface{}
+	Load     []string
+}
+
+// ResolveResponse mirrors the ResolveResponse message of resolver.proto.
+//
+// Property:
+//   - Context: the loaded values by param.
+//   - Errors: the errors by param.
+//   - Error: the error of the whole call.
+type ResolveResponse struct {
+	Context map[string]interface{}
+	Errors  map[string]interface{}
+	Error   string
+}
+
+// Marshal encodes the request on the protobuf wire format
+func (r *ResolveRequest) Marshal() ([]byte, error) {
+	var b []byte
+	if r.Resolver != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, r.Resolver)
+	}
+
+	b, err := appendStruct(b, 2, r.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, param := range r.Load {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, param)
+	}
+	return b, nil
+}
+
+// Unmarshal decodes the request from the protobuf wire format
+func (r *ResolveRequest) Unmarshal(data []byte) error {
+	*r = ResolveRequest{}
+	return consumeFields(data, func(num protowire.Number, value []byte) (err error) {
+		switch num {
+		case 1:
+			r.Resolver = string(value)
+		case 2:
+			r.Context, err = decodeStruct(value)
+		case 3:
+			r.Load = append(r.Load, string(value))
+		}
+		return
+	})
+}
+
+// Marshal encodes the response on the protobuf wire format
+func (r *ResolveResponse) Marshal() ([]byte, error) {
+	b, err := appendStruct(nil, 1, r.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = appendStruct(b, 2, r.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Error != "" {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, r.Error)
+	}
+	return b, nil
+}
+
+// Unmarshal decodes the response from the protobuf wire format
+func (r *ResolveResponse) Unmarshal(data []byte) error {
+	*r = ResolveResponse{}
+	return consumeFields(data, func(num protowire.Number, value []byte) (err error) {
+		switch num {
+		case 1:
+			r.Context, err = decodeStruct(value)
+		case 2:
+			r.Errors, err = decodeStruct(value)
+		case 3:
+			r.Error = string(value)
+		}
+		return
+	})
+}
+
+// appendStruct appends a map as a google.protobuf.Struct field, skipping it when the map is nil.
+func appendStruct(b []byte, num protowire.Number, values map[string]interface{}) ([]byte, error) {
+	if values == nil {
+		return b, nil
+	}
+
+	message, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, data), nil
+}
+
+// decodeStruct decodes a google.protobuf.Struct field into a map.
+func decodeStruct(data []byte) (map[string]interface{}, error) {
+	message := &structpb.Struct{}
+	err := proto.Unmarshal(data, message)
+	if err != nil {
+		return nil, err
+	}
+	return message.AsMap(), nil
+}
+
+// consumeFields calls the callback with the number and the content of each length-delimited field of
+// a message. The fields of other wire types are skipped, as unknown fields.
+func consumeFields(data []byte, callback func(num protowire.Number, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		err := callback(num, value)
+		if err != nil {
+			return fmt.Errorf("error on decode the field %d: %w", num, err)
+		}
+	}
+	return nil
+}
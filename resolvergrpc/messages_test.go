@@ -0,0 +1,74 @@
+package resolvergrpc
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TestResolveRequestRoundTrip checks if a request is decoded as it was encoded.
+func TestResolveRequestRoundTrip(t *testing.T) {
+	expected := &ResolveRequest{
+		Resolver: "customer",
+		Context:  map[string]interface{}{"branch": "0001", "amount": 10.5, "tags": []interface{}{"a", true}},
+		Load:     []string{"age", "name"},
+	}
+
+	data, err := expected.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &ResolveRequest{}
+	err = got.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
+
+// TestResolveResponseRoundTrip checks if a response is decoded as it was encoded.
+func TestResolveResponseRoundTrip(t *testing.T) {
+	expected := &ResolveResponse{
+		Context: map[string]interface{}{"age": 21.0},
+		Errors:  map[string]interface{}{"name": "not found"},
+		Error:   "partial",
+	}
+
+	data, err := expected.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &ResolveResponse{}
+	err = got.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
+
+// TestUnmarshalSkipsUnknownFields checks if the unknown fields of newer versions of the contract are skipped.
+func TestUnmarshalSkipsUnknownFields(t *testing.T) {
+	data := protowire.AppendTag(nil, 9, protowire.VarintType)
+	data = protowire.AppendVarint(data, 42)
+	data = protowire.AppendTag(data, 3, protowire.BytesType)
+	data = protowire.AppendString(data, "myerror")
+
+	got := &ResolveResponse{}
+	err := got.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Error != "myerror" {
+		t.Errorf("Test Fail, we want %v, we got %v", "myerror", got.Error)
+	}
+}
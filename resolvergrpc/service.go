@@ -0,0 +1,97 @@
+package resolvergrpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// ResolveMethod is the full name of the Resolve method of the ResolverBridge service.
+const ResolveMethod = "/featws.resolver.v1.ResolverBridge/Resolve"
+
+// Codec is the gRPC codec of the messages of the resolver bridge service. It's named "proto" because
+// the messages are encoded on the protobuf wire format, so it talks with any server generated from
+// resolver.proto.
+type Codec struct{}
+
+// Marshal encodes a Message
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(Message)
+	if !ok {
+		return nil, fmt.Errorf("unsupported message type %T", v)
+	}
+	return message.Marshal()
+}
+
+// Unmarshal decodes a Message
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(Message)
+	if !ok {
+		return fmt.Errorf("unsupported message type %T", v)
+	}
+	return message.Unmarshal(data)
+}
+
+// Name returns the name of the codec
+func (Codec) Name() string {
+	return "proto"
+}
+
+// Resolve calls the Resolve method of the resolver bridge on the connection.
+func Resolve(ctx context.Context, conn grpc.ClientConnInterface, req *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error) {
+	resp := &ResolveResponse{}
+	opts = append(opts, grpc.ForceCodec(Codec{}))
+	err := conn.Invoke(ctx, ResolveMethod, req, resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ResolverBridgeServer is the server side of the ResolverBridge service.
+type ResolverBridgeServer interface {
+	Resolve(ctx context.Context, req *ResolveRequest) (*ResolveResponse, error)
+}
+
+// serviceDesc describes the ResolverBridge service for the gRPC server.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "featws.resolver.v1.ResolverBridge",
+	HandlerType: (*ResolverBridgeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Resolve",
+			Handler:    resolveHandler,
+		},
+	},
+	Metadata: "resolver.proto",
+}
+
+// resolveHandler decodes the request and calls the Resolve method of the server.
+func resolveHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ResolveRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ResolverBridgeServer).Resolve(ctx, req)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResolveMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResolverBridgeServer).Resolve(ctx, req.(*ResolveRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+// NewServer creates a gRPC server with the codec of the resolver bridge messages and registers the
+// ResolverBridge service on it.
+func NewServer(srv ResolverBridgeServer, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(opts, grpc.ForceServerCodec(Codec{}))...)
+	server.RegisterService(&serviceDesc, srv)
+	return server
+}
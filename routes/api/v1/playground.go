@@ -0,0 +1,12 @@
+package v1
+
+import (
+	v1 "github.com/bancodobrasil/featws-ruller/controllers/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// playgroundRouter sets up the route for evaluating uploaded rulesheets using the Gin framework.
+func playgroundRouter(router *gin.RouterGroup) {
+	router.POST("/", v1.PlaygroundHandler())
+	router.POST("", v1.PlaygroundHandler())
+}
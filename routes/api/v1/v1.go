@@ -5,8 +5,10 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// Router sets up a router with authentication middleware and a sub-router for evaluating code.
+// Router sets up a router with authentication middleware and the sub-routers for evaluating published
+// and uploaded rulesheets.
 func Router(router *gin.RouterGroup) {
 	router.Use(goauthgin.Authenticate())
 	evalRouter(router.Group("/eval"))
+	playgroundRouter(router.Group("/playground"))
 }
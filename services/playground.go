@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/engine"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+
+	"github.com/bancodobrasil/featws-ruller/types"
+)
+
+// TemporaryKnowledgeBaseName its the name of the knowledge bases built from an uploaded rulesheet
+const TemporaryKnowledgeBaseName = "temporary"
+
+// TemporaryKnowledgeBaseVersion its the version of the knowledge bases built from an uploaded rulesheet
+const TemporaryKnowledgeBaseVersion = "temporary"
+
+// EvalOptions contains the limits and the flags applied on an evaluation.
+//
+// Property:
+//   - MaxCycle: the maximum number of cycles the grule engine can run before aborting the evaluation. Zero keeps the engine default.
+//   - Timeout: the maximum duration of the evaluation. Zero means no time limit.
+//   - Explain: indicates whether the rule entries evaluated and executed by the engine must be recorded on a Trace.
+type EvalOptions struct {
+	MaxCycle uint64
+	Timeout  time.Duration
+	Explain  bool
+}
+
+// TraceEntry represents an action taken by the grule engine over a rule entry.
+//
+// Property:
+//   - Cycle: the engine cycle in which the action was taken.
+//   - Rule: the name of the rule entry.
+//   - Action: "evaluate" when the rule "when" scope was evaluated and "execute" when the rule "then" scope was executed.
+//   - Candidate: indicates whether the evaluated rule entry was selected as a candidate to be executed.
+type TraceEntry struct {
+	Cycle     uint64 `json:"cycle"`
+	Rule      string `json:"rule"`
+	Action    string `json:"action"`
+	Candidate bool   `json:"candidate,omitempty"`
+}
+
+// Trace records the rule entries evaluated and executed by the grule engine. It implements the
+// `engine.GruleEngineListener` interface.
+type Trace struct {
+	Entries []TraceEntry `json:"entries"`
+}
+
+// NewTrace creates a new empty Trace
+func NewTrace() *Trace {
+	return &Trace{
+		Entries: []TraceEntry{},
+	}
+}
+
+// EvaluateRuleEntry records that the "when" scope of a rule entry was evaluated
+func (t *Trace) EvaluateRuleEntry(cycle uint64, entry *ast.RuleEntry, candidate bool) {
+	t.Entries = append(t.Entries, TraceEntry{
+		Cycle:     cycle,
+		Rule:      entry.RuleName,
+		Action:    "evaluate",
+		Candidate: candidate,
+	})
+}
+
+// ExecuteRuleEntry records that the "then" scope of a rule entry was executed
+func (t *Trace) ExecuteRuleEntry(cycle uint64, entry *ast.RuleEntry) {
+	t.Entries = append(t.Entries, TraceEntry{
+		Cycle:  cycle,
+		Rule:   entry.RuleName,
+		Action: "execute",
+	})
+}
+
+// BeginCycle is called by the engine on the start of each cycle. The cycle is already recorded on
+// each entry, so there is nothing to do here.
+func (t *Trace) BeginCycle(cycle uint64) {}
+
+// BuildTemporaryKnowledgeBase builds a GRL source into a knowledge base of a new knowledge library, so
+// the rules are evaluated in isolation and never pollute the shared knowledge library of the service.
+func (s Eval) BuildTemporaryKnowledgeBase(grl string) (*ast.KnowledgeBase, error) {
+	library := ast.NewKnowledgeLibrary()
+	ruleBuilder := builder.NewRuleBuilder(library)
+	err := ruleBuilder.BuildRuleFromResource(TemporaryKnowledgeBaseName, TemporaryKnowledgeBaseVersion, pkg.NewBytesResource([]byte(grl)))
+	if err != nil {
+		return nil, err
+	}
+	return library.GetKnowledgeBase(TemporaryKnowledgeBaseName, TemporaryKnowledgeBaseVersion), nil
+}
+
+// EvalWithOptions evaluates the context against a knowledge base applying the cycle and time limits of
+// the options. When the options ask for an explanation, it returns the Trace of the evaluation as well.
+// It doesn't synchronize with the loading of the shared knowledge library, so it must be used only with
+// isolated knowledge bases, built by BuildTemporaryKnowledgeBase or loaded by LoadIsolatedKnowledgeBase.
+func (s Eval) EvalWithOptions(ctx *types.Context, knowledgeBase *ast.KnowledgeBase, opts EvalOptions) (*types.Result, *Trace, error) {
+	rawCtx := ctx.RawContext
+	if rawCtx == nil {
+		rawCtx = context.Background()
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		rawCtx, cancel = context.WithTimeout(rawCtx, opts.Timeout)
+		defer cancel()
+		ctx.RawContext = rawCtx
+	}
+
+	eng := engine.NewGruleEngine()
+	if opts.MaxCycle > 0 {
+		eng.MaxCycle = opts.MaxCycle
+	}
+
+	var trace *Trace
+	if opts.Explain {
+		trace = NewTrace()
+		eng.Listeners = append(eng.Listeners, trace)
+	}
+
+	result, err := s.execute(rawCtx, eng, ctx, knowledgeBase)
+	return result, trace, err
+}
@@ -160,6 +160,9 @@ var evalWg sync.WaitGroup
 //   - LoadLocalGRL: is a method that loads a GRL (Guideline Representation Language) file from the local file system and adds its contents to a specified knowledge base with a given version. The method takes in the path of the GRL file, the name of the knowledge base, and the version
 //   - {error} LoadRemoteGRL - LoadRemoteGRL is a method that loads a GRL (Guideline Representation Language) file from a remote location into the knowledge base specified by the knowledgeBaseName and version parameters. This method is used to retrieve the rules and facts from a remote source and add them to the knowledge base for evaluation
 //   - Eval - Eval is a method that takes in a context and a knowledge base and evaluates the rules in the knowledge base based on the context. It returns a result and an error if there was an issue during evaluation.
+//   - BuildTemporaryKnowledgeBase - builds a GRL source into an isolated knowledge base that isn't stored on the shared knowledge library.
+//   - EvalWithOptions - evaluates a context against a knowledge base with cycle and time limits, optionally returning the trace of the evaluated and executed rules.
+//   - LoadIsolatedKnowledgeBase - loads a remote rulesheet into a new knowledge library, apart from the shared one, without synchronizing with the served evaluations.
 type IEval interface {
 	GetKnowledgeLibrary() *ast.KnowledgeLibrary
 	GetDefaultKnowledgeBase() *ast.KnowledgeBase
@@ -167,6 +170,9 @@ type IEval interface {
 	LoadLocalGRL(grlPath string, knowledgeBaseName string, version string) error
 	LoadRemoteGRL(ctx context.Context, knowledgeBaseName string, version string) error
 	Eval(ctx *types.Context, knowledgeBase *ast.KnowledgeBase) (*types.Result, error)
+	BuildTemporaryKnowledgeBase(grl string) (*ast.KnowledgeBase, error)
+	EvalWithOptions(ctx *types.Context, knowledgeBase *ast.KnowledgeBase, opts EvalOptions) (*types.Result, *Trace, error)
+	LoadIsolatedKnowledgeBase(ctx context.Context, knowledgeBaseName string, version string) (*ast.KnowledgeLibrary, error)
 }
 
 // EvalService is a variable type of `IEval` and initializing it with a new instance of the `Eval` struct created by calling the `NewEval()`
@@ -278,6 +284,25 @@ func (s Eval) GetKnowledgeBase(ctx context.Context, knowledgeBaseName string, ve
 
 }
 
+// LoadIsolatedKnowledgeBase loads a remote rulesheet into a new knowledge library, apart from the shared
+// knowledge library of the service, so the loading never waits for nor blocks the served evaluations.
+// The knowledge bases of the returned library must be evaluated with EvalWithOptions, each evaluation
+// with its own instance created by `NewKnowledgeBaseInstance`.
+func (s Eval) LoadIsolatedKnowledgeBase(ctx context.Context, knowledgeBaseName string, version string) (*ast.KnowledgeLibrary, error) {
+	isolated := Eval{knowledgeLibrary: ast.NewKnowledgeLibrary()}
+
+	err := isolated.LoadRemoteGRL(ctx, knowledgeBaseName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(isolated.knowledgeLibrary.GetKnowledgeBase(knowledgeBaseName, version).RuleEntries) == 0 {
+		return nil, fmt.Errorf("the knowledge base %s or its version %s wasn't found", knowledgeBaseName, version)
+	}
+
+	return isolated.knowledgeLibrary, nil
+}
+
 // Eval ...
 func (s Eval) Eval(ctx *types.Context, knowledgeBase *ast.KnowledgeBase) (result *types.Result, err error) {
 
@@ -292,6 +317,13 @@ func (s Eval) Eval(ctx *types.Context, knowledgeBase *ast.KnowledgeBase) (result
 	evalWg.Add(1)
 	defer evalWg.Done()
 
+	return s.execute(context.Background(), engine.NewGruleEngine(), ctx, knowledgeBase)
+}
+
+// execute runs the grule engine over the knowledge base with the processor, the context and a new
+// result added to the data context. Any panic raised during the execution is recovered and returned
+// as an error.
+func (s Eval) execute(rawCtx context.Context, eng *engine.GruleEngine, ctx *types.Context, knowledgeBase *ast.KnowledgeBase) (result *types.Result, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("recovered from panic: %v", r)
@@ -322,8 +354,12 @@ func (s Eval) Eval(ctx *types.Context, knowledgeBase *ast.KnowledgeBase) (result
 		return
 	}
 
-	eng := engine.NewGruleEngine()
-	err = eng.Execute(dataCtx, knowledgeBase)
+	err = eng.ExecuteWithContext(rawCtx, dataCtx, knowledgeBase)
+	if failure := ctx.Failure(); failure != nil {
+		err = failure
+		log.Errorf("error on load a remote loaded param: %v", err)
+		return
+	}
 	if err != nil {
 		log.Error("error on execute the grule engine: %w", err)
 		return
@@ -0,0 +1,282 @@
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/tester"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	log "github.com/sirupsen/logrus"
+)
+
+// evaluations counts the shadow evaluations by knowledge base, candidate version and outcome, that can
+// be "match", "mismatch", "unresolved" when the features differ but the candidate version used params
+// that the served evaluation didn't load, "error" or "skipped" when there was no free slot to evaluate.
+var evaluations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "featws_ruller_shadow_evaluations_total",
+	Help: "Shadow evaluations of the candidate version by outcome.",
+}, []string{"knowledge_base", "candidate", "outcome"})
+
+// mismatches counts the features of the candidate version that differ from the served ones, by
+// knowledge base, candidate version and feature.
+var mismatches = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "featws_ruller_shadow_mismatches_total",
+	Help: "Features of the candidate version that differ from the served ones.",
+}, []string{"knowledge_base", "candidate", "feature"})
+
+// unresolvedParams counts the remote loaded params used by the candidate version that the served
+// evaluation didn't load, by knowledge base, candidate version and param.
+var unresolvedParams = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "featws_ruller_shadow_unresolved_params_total",
+	Help: "Remote loaded params of the candidate version that the served evaluation didn't load.",
+}, []string{"knowledge_base", "candidate", "param"})
+
+// candidateRetry is the delay before loading again a candidate version that failed to load.
+const candidateRetry = time.Minute
+
+// candidateRefresh is the delay before loading again a candidate version that was loaded, so the changes
+// of its resources, like a moving version, reach the shadow evaluations.
+const candidateRefresh = 5 * time.Minute
+
+// candidate is the candidate version of a knowledge base, loaded into an isolated knowledge library.
+//
+// Property:
+//   - library: the isolated knowledge library with the candidate version.
+//   - err: the error of the last load of the candidate version, when there's no library loaded before.
+//   - expiresAt: the time to load the candidate version again, after candidateRefresh or, on failures, after candidateRetry.
+type candidate struct {
+	library   *ast.KnowledgeLibrary
+	err       error
+	expiresAt time.Time
+}
+
+// Shadow evaluates a candidate version of the knowledge bases in the background for a sample of the
+// served requests, comparing its features with the served ones. The candidate version is loaded into
+// an isolated knowledge library and evaluated with the values already resolved by the served
+// evaluation, so the shadow evaluation never takes the locks of the served ones nor calls the resolvers.
+// The params used only by the candidate version fail as unresolved, so an evaluation that differs with
+// any of them is counted as "unresolved" instead of as a mismatch of its features.
+//
+// Property:
+//   - evalService: the service used to load and evaluate the candidate version.
+//   - knowledgeBase: the only knowledge base shadowed. When it's empty, all knowledge bases are shadowed.
+//   - version: the candidate version.
+//   - sampleRate: the fraction, from 0 to 1, of the served requests evaluated with the candidate version.
+//   - logSampleRate: the fraction, from 0 to 1, of the mismatches that are logged.
+//   - slots: bounds the number of concurrent shadow evaluations. When there is no free slot, the request isn't shadowed.
+//   - candidates: the loaded candidate versions by knowledge base name.
+type Shadow struct {
+	evalService     services.IEval
+	knowledgeBase   string
+	version         string
+	sampleRate      float64
+	logSampleRate   float64
+	slots           chan struct{}
+	wg              sync.WaitGroup
+	candidates      map[string]*candidate
+	candidatesMutex sync.Mutex
+}
+
+// Default is the Shadow used by the eval endpoint. It's nil when the shadow evaluation is disabled.
+var Default *Shadow
+
+// New creates a new Shadow of the candidate version
+func New(evalService services.IEval, knowledgeBase string, version string, sampleRate float64, logSampleRate float64, concurrency int) *Shadow {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Shadow{
+		evalService:   evalService,
+		knowledgeBase: knowledgeBase,
+		version:       version,
+		sampleRate:    sampleRate,
+		logSampleRate: logSampleRate,
+		slots:         make(chan struct{}, concurrency),
+		candidates:    make(map[string]*candidate),
+	}
+}
+
+// Setup sets the Default Shadow with the candidate version configured on `FEATWS_RULLER_SHADOW_VERSION`.
+// It does nothing when there is no candidate version configured.
+func Setup(cfg *config.Config, evalService services.IEval) {
+	if cfg.ShadowVersion == "" {
+		return
+	}
+
+	log.Infof("Shadowing %.2f%% of the requests with the version '%s'", cfg.ShadowSampleRate*100, cfg.ShadowVersion)
+	Default = New(evalService, cfg.ShadowKnowledgeBase, cfg.ShadowVersion, cfg.ShadowSampleRate, cfg.ShadowLogSampleRate, int(cfg.ShadowConcurrency))
+	if cfg.ShadowKnowledgeBase != "" {
+		Default.Preload(cfg.ShadowKnowledgeBase)
+	}
+}
+
+// Preload loads the candidate version of the knowledge base in the background, so the first shadowed
+// requests don't wait for it.
+func (s *Shadow) Preload(knowledgeBaseName string) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		_, err := s.candidate(knowledgeBaseName)
+		if err != nil {
+			log.WithField("knowledgeBase", knowledgeBaseName).WithField("candidate", s.version).Errorf("Error on load the candidate version: %v", err)
+		}
+	}()
+}
+
+// Submit samples a served request to be evaluated with the candidate version in the background. The
+// values are the entries of the context of the served evaluation, with the remote loaded params it
+// resolved, which are reused by the candidate version. It never blocks the served request and returns
+// whether the request was shadowed.
+func (s *Shadow) Submit(knowledgeBaseName string, version string, values map[string]interface{}, features map[string]interface{}) bool {
+	if version == s.version || (s.knowledgeBase != "" && knowledgeBaseName != s.knowledgeBase) {
+		return false
+	}
+
+	if rand.Float64() >= s.sampleRate {
+		return false
+	}
+
+	// The values are copied, so the background evaluation never shares them with the served request
+	values, err := tester.Normalize(values)
+	if err != nil {
+		evaluations.WithLabelValues(knowledgeBaseName, s.version, "error").Inc()
+		return false
+	}
+
+	served, err := tester.Normalize(features)
+	if err != nil {
+		evaluations.WithLabelValues(knowledgeBaseName, s.version, "error").Inc()
+		return false
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		evaluations.WithLabelValues(knowledgeBaseName, s.version, "skipped").Inc()
+		return false
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.slots }()
+		s.evaluate(knowledgeBaseName, values, served)
+	}()
+	return true
+}
+
+// Wait blocks until all the submitted shadow evaluations are done.
+func (s *Shadow) Wait() {
+	s.wg.Wait()
+}
+
+// evaluate evaluates the values with the candidate version and records the outcome. Any failure is only
+// recorded, it never reaches the served request.
+func (s *Shadow) evaluate(knowledgeBaseName string, values map[string]interface{}, served map[string]interface{}) {
+	logger := log.WithField("knowledgeBase", knowledgeBaseName).WithField("candidate", s.version)
+
+	defer func() {
+		if r := recover(); r != nil {
+			evaluations.WithLabelValues(knowledgeBaseName, s.version, "error").Inc()
+			logger.Errorf("Error on shadow eval: %v", r)
+		}
+	}()
+
+	got, unresolved, err := s.eval(knowledgeBaseName, values)
+	if err != nil {
+		evaluations.WithLabelValues(knowledgeBaseName, s.version, "error").Inc()
+		logger.Errorf("Error on shadow eval: %v", err)
+		return
+	}
+
+	keys := tester.DiffKeys(served, got)
+	if len(keys) == 0 {
+		evaluations.WithLabelValues(knowledgeBaseName, s.version, "match").Inc()
+		return
+	}
+
+	if len(unresolved) > 0 {
+		evaluations.WithLabelValues(knowledgeBaseName, s.version, "unresolved").Inc()
+		for _, param := range unresolved {
+			unresolvedParams.WithLabelValues(knowledgeBaseName, s.version, param).Inc()
+		}
+		return
+	}
+
+	evaluations.WithLabelValues(knowledgeBaseName, s.version, "mismatch").Inc()
+	for _, key := range keys {
+		mismatches.WithLabelValues(knowledgeBaseName, s.version, key).Inc()
+	}
+
+	if rand.Float64() < s.logSampleRate {
+		logger.WithField("context", values).Warnf("Shadow mismatch: %v", tester.Diff(served, got))
+	}
+}
+
+// candidate returns the isolated knowledge library with the candidate version of the knowledge base,
+// loading it on the first use and again after candidateRefresh. A failed load is retried only after
+// candidateRetry, keeping the library loaded before, if there's one, until then.
+func (s *Shadow) candidate(knowledgeBaseName string) (*ast.KnowledgeLibrary, error) {
+	s.candidatesMutex.Lock()
+	defer s.candidatesMutex.Unlock()
+
+	loaded, ok := s.candidates[knowledgeBaseName]
+	if ok && time.Now().Before(loaded.expiresAt) {
+		return loaded.library, loaded.err
+	}
+
+	library, err := s.evalService.LoadIsolatedKnowledgeBase(context.Background(), knowledgeBaseName, s.version)
+	if err != nil {
+		if ok && loaded.library != nil {
+			log.WithField("knowledgeBase", knowledgeBaseName).WithField("candidate", s.version).Errorf("Error on refresh the candidate version: %v", err)
+			library, err = loaded.library, nil
+		}
+		s.candidates[knowledgeBaseName] = &candidate{library: library, err: err, expiresAt: time.Now().Add(candidateRetry)}
+		return library, err
+	}
+
+	s.candidates[knowledgeBaseName] = &candidate{library: library, expiresAt: time.Now().Add(candidateRefresh)}
+	return library, nil
+}
+
+// eval evaluates the values with an instance of the candidate version of the knowledge base. The remote
+// loaded params are never resolved again: the ones that the served evaluation didn't load fail and are
+// returned as unresolved, with their errors left out of the features.
+func (s *Shadow) eval(knowledgeBaseName string, values map[string]interface{}) (map[string]interface{}, []string, error) {
+	library, err := s.candidate(knowledgeBaseName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on load the candidate version: %w", err)
+	}
+
+	ctx := types.NewResolvedContextFromMap(values)
+	ctx.RawContext = context.Background()
+
+	result, _, err := s.evalService.EvalWithOptions(ctx, library.NewKnowledgeBaseInstance(knowledgeBaseName, s.version), services.EvalOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	features, err := tester.Normalize(result.GetFeatures())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unresolved := ctx.UnresolvedParams()
+	if errs, ok := features["errors"].(map[string]interface{}); ok {
+		for _, param := range unresolved {
+			delete(errs, param)
+		}
+		if len(errs) == 0 {
+			delete(features, "errors")
+		}
+	}
+	return features, unresolved, nil
+}
@@ -0,0 +1,231 @@
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+	"github.com/hyperjumptech/grule-rule-engine/builder"
+	"github.com/hyperjumptech/grule-rule-engine/pkg"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// candidateRules is the candidate rulesheet of the tests, which uses a remote loaded param.
+const candidateRules = `
+	rule feat_small salience 9 {
+		when
+			true
+		then
+			ctx.RegistryRemoteLoadedWithFallback("score", "scorer", 0);
+			result.Put("small", ctx.GetInt("mynumber") < 12);
+			result.Put("goodScore", ctx.GetInt("score") > 500);
+			Retract("feat_small");
+	}
+`
+
+// EvalServiceTestShadow is an evaluation service that loads the candidate version from a local
+// rulesheet instead of the resource loader.
+//
+// Property:
+//   - loads: the number of loads of the candidate version.
+type EvalServiceTestShadow struct {
+	services.IEval
+	loads *int
+}
+
+// LoadIsolatedKnowledgeBase builds the local rulesheet when the candidate version is requested.
+func (s EvalServiceTestShadow) LoadIsolatedKnowledgeBase(ctx context.Context, knowledgeBaseName string, version string) (*ast.KnowledgeLibrary, error) {
+	*s.loads++
+	if version != "2" {
+		return nil, fmt.Errorf("the knowledge base %s or its version %s wasn't found", knowledgeBaseName, version)
+	}
+
+	library := ast.NewKnowledgeLibrary()
+	err := builder.NewRuleBuilder(library).BuildRuleFromResource(knowledgeBaseName, version, pkg.NewBytesResource([]byte(candidateRules)))
+	return library, err
+}
+
+// countingHTTPClient is a resolver bridge client that counts the calls and always fails.
+type countingHTTPClient struct {
+	calls int
+}
+
+// Do counts the call and fails
+func (c *countingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return nil, fmt.Errorf("unexpected call to %s", req.URL)
+}
+
+// newEvalServiceTestShadow creates the evaluation service with the candidate rulesheet.
+func newEvalServiceTestShadow(t *testing.T) EvalServiceTestShadow {
+	return EvalServiceTestShadow{IEval: services.NewEval(config.GetConfig()), loads: new(int)}
+}
+
+// TestSubmit checks if the matches and the mismatches of the candidate version are counted.
+func TestSubmit(t *testing.T) {
+	evalService := newEvalServiceTestShadow(t)
+	s := New(evalService, "", "2", 1, 1, 1)
+
+	if !s.Submit("mykb", "latest", map[string]interface{}{"mynumber": 1, "score": 700}, map[string]interface{}{"small": true, "goodScore": true}) {
+		t.Fatal("the request must be shadowed")
+	}
+	s.Wait()
+
+	if !s.Submit("mykb", "latest", map[string]interface{}{"mynumber": 20, "score": 700}, map[string]interface{}{"small": true, "goodScore": true}) {
+		t.Fatal("the request must be shadowed")
+	}
+	s.Wait()
+
+	if got := testutil.ToFloat64(evaluations.WithLabelValues("mykb", "2", "match")); got != 1 {
+		t.Errorf("expected 1 match, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(evaluations.WithLabelValues("mykb", "2", "mismatch")); got != 1 {
+		t.Errorf("expected 1 mismatch, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(mismatches.WithLabelValues("mykb", "2", "small")); got != 1 {
+		t.Errorf("expected 1 mismatch of small, got %v", got)
+	}
+
+	if *evalService.loads != 1 {
+		t.Errorf("expected the candidate version to be loaded once, got %d loads", *evalService.loads)
+	}
+}
+
+// TestSubmitResolvedValues checks if the candidate version reuses the remote loaded params resolved by
+// the served evaluation, and never calls the resolver bridge for the others.
+func TestSubmitResolvedValues(t *testing.T) {
+	client := &countingHTTPClient{}
+	previous := types.Client
+	types.Client = client
+	t.Cleanup(func() {
+		types.Client = previous
+	})
+
+	s := New(newEvalServiceTestShadow(t), "", "2", 1, 1, 1)
+
+	// The served evaluation failed to load the score, so the candidate applies the fallback with the same error
+	s.Submit("resolvedkb", "latest",
+		map[string]interface{}{"mynumber": 1, "errors": map[string]interface{}{"score": []interface{}{"score unavailable"}}},
+		map[string]interface{}{"small": true, "goodScore": false, "errors": map[string]interface{}{"score": []interface{}{"score unavailable"}}},
+	)
+	s.Wait()
+
+	if got := testutil.ToFloat64(evaluations.WithLabelValues("resolvedkb", "2", "match")); got != 1 {
+		t.Errorf("expected 1 match, got %v", got)
+	}
+
+	// The served evaluation didn't load the score, so the candidate fails it instead of calling the bridge,
+	// leaving its error out of the comparison
+	s.Submit("resolvedkb", "latest", map[string]interface{}{"mynumber": 1}, map[string]interface{}{"small": true, "goodScore": false})
+	s.Wait()
+
+	if got := testutil.ToFloat64(evaluations.WithLabelValues("resolvedkb", "2", "match")); got != 2 {
+		t.Errorf("expected 2 matches, got %v", got)
+	}
+
+	// The served version doesn't have the feature that uses the unresolved score, so the difference isn't a mismatch
+	s.Submit("resolvedkb", "latest", map[string]interface{}{"mynumber": 1}, map[string]interface{}{"small": true})
+	s.Wait()
+
+	if got := testutil.ToFloat64(evaluations.WithLabelValues("resolvedkb", "2", "unresolved")); got != 1 {
+		t.Errorf("expected 1 unresolved evaluation, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(unresolvedParams.WithLabelValues("resolvedkb", "2", "score")); got != 1 {
+		t.Errorf("expected 1 unresolved score, got %v", got)
+	}
+
+	for _, feature := range []string{"goodScore", "errors"} {
+		if got := testutil.ToFloat64(mismatches.WithLabelValues("resolvedkb", "2", feature)); got != 0 {
+			t.Errorf("expected no mismatch of %s, got %v", feature, got)
+		}
+	}
+
+	if client.calls != 0 {
+		t.Errorf("expected no call to the resolver bridge, got %d calls", client.calls)
+	}
+}
+
+// TestSubmitNotSampled checks if the requests out of the sample, of other knowledge bases or of the
+// candidate version itself aren't shadowed.
+func TestSubmitNotSampled(t *testing.T) {
+	values := map[string]interface{}{"mynumber": 1}
+	features := map[string]interface{}{"small": true}
+
+	if New(newEvalServiceTestShadow(t), "", "2", 0, 1, 1).Submit("mykb", "latest", values, features) {
+		t.Error("the request out of the sample must not be shadowed")
+	}
+
+	if New(newEvalServiceTestShadow(t), "otherkb", "2", 1, 1, 1).Submit("mykb", "latest", values, features) {
+		t.Error("the request of other knowledge base must not be shadowed")
+	}
+
+	if New(newEvalServiceTestShadow(t), "", "2", 1, 1, 1).Submit("mykb", "2", values, features) {
+		t.Error("the request of the candidate version must not be shadowed")
+	}
+}
+
+// TestSubmitError checks if a failure of the candidate version is only counted.
+func TestSubmitError(t *testing.T) {
+	evalService := newEvalServiceTestShadow(t)
+	s := New(evalService, "", "3", 1, 1, 1)
+
+	for i := 0; i < 2; i++ {
+		s.Submit("errorkb", "latest", map[string]interface{}{}, map[string]interface{}{})
+		s.Wait()
+	}
+
+	if got := testutil.ToFloat64(evaluations.WithLabelValues("errorkb", "3", "error")); got != 2 {
+		t.Errorf("expected 2 errors, got %v", got)
+	}
+
+	if *evalService.loads != 1 {
+		t.Errorf("expected the failed load not to be retried before %v, got %d loads", candidateRetry, *evalService.loads)
+	}
+}
+
+// TestCandidateRefresh checks if a loaded candidate version is loaded again after it expires, keeping the
+// library loaded before when the new load fails.
+func TestCandidateRefresh(t *testing.T) {
+	evalService := newEvalServiceTestShadow(t)
+	s := New(evalService, "", "2", 1, 1, 1)
+
+	library, err := s.candidate("refreshkb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.candidate("refreshkb")
+
+	if *evalService.loads != 1 {
+		t.Errorf("expected the candidate version to be loaded once before it expires, got %d loads", *evalService.loads)
+	}
+
+	s.candidates["refreshkb"].expiresAt = time.Now()
+	refreshed, err := s.candidate("refreshkb")
+	if err != nil || refreshed == library {
+		t.Errorf("expected the candidate version to be loaded again, got %v", err)
+	}
+
+	s.candidates["refreshkb"].expiresAt = time.Now()
+	s.version = "3"
+	kept, err := s.candidate("refreshkb")
+	if err != nil || kept != refreshed {
+		t.Errorf("expected the library loaded before to be kept on a failed refresh, got %v", err)
+	}
+
+	if *evalService.loads != 3 {
+		t.Errorf("expected 3 loads, got %d", *evalService.loads)
+	}
+
+	if time.Until(s.candidates["refreshkb"].expiresAt) > candidateRetry {
+		t.Errorf("expected the failed refresh to be retried after %v", candidateRetry)
+	}
+}
@@ -0,0 +1,95 @@
+package tester
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a JUnit XML test suite, one for each rulesheet.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a JUnit XML test case, one for each case of the rulesheet.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure describes why a JUnit XML test case failed.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as a JUnit XML, so it can be consumed by CI tools.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:     r.Name,
+		Tests:    len(r.Results),
+		Failures: r.Failed(),
+		Cases:    make([]junitTestCase, 0, len(r.Results)),
+	}
+
+	var total time.Duration
+	for _, result := range r.Results {
+		total += result.Duration
+
+		testCase := junitTestCase{
+			Name:      result.Case.Name,
+			ClassName: r.Name,
+			Time:      formatSeconds(result.Duration),
+		}
+
+		if !result.Passed() {
+			lines := append([]string{}, result.Diffs...)
+			message := "unexpected result"
+			if result.Error != nil {
+				message = result.Error.Error()
+				lines = append([]string{message}, lines...)
+			}
+			testCase.Failure = &junitFailure{
+				Message: message,
+				Content: strings.Join(lines, "\n"),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = formatSeconds(total)
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// formatSeconds formats a duration as seconds like JUnit XML expects.
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,294 @@
+package tester
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// Case is a golden test case of a rulesheet, read from a JSON file of the cases directory.
+//
+// Property:
+//   - Name: the name of the case. When it's empty, the name of the file is used.
+//   - Context: the parameters of the evaluation.
+//   - Resolvers: the stubbed values of the remote loaded params by resolver name and param. The resolver bridge is never called, so a remote loaded param without stub is recorded on the `errors` of the result.
+//   - Features: the features expected on the result.
+//   - RequiredParamErrors: the required param errors expected on the result. When it's empty, the result must not have required param errors.
+type Case struct {
+	Name                string                 `json:"name,omitempty"`
+	Context             map[string]interface{} `json:"context"`
+	Resolvers           types.StubResolver     `json:"resolvers,omitempty"`
+	Features            map[string]interface{} `json:"features"`
+	RequiredParamErrors map[string]interface{} `json:"requiredParamErrors,omitempty"`
+}
+
+// CaseResult is the outcome of running a Case.
+//
+// Property:
+//   - Case: the case that was run.
+//   - Diffs: the differences between the expected and the got result. The case passes when there are no diffs and no error.
+//   - Error: the error returned by the evaluation, if any.
+//   - Duration: the time spent evaluating the case.
+type CaseResult struct {
+	Case     *Case
+	Diffs    []string
+	Error    error
+	Duration time.Duration
+}
+
+// Passed verifies if the case got the expected result.
+func (r *CaseResult) Passed() bool {
+	return r.Error == nil && len(r.Diffs) == 0
+}
+
+// Report gathers the results of all cases run against a rulesheet.
+type Report struct {
+	Name    string
+	Results []*CaseResult
+}
+
+// Failed returns the number of cases that didn't pass.
+func (r *Report) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// LoadCases reads all JSON files of a directory as test cases, sorted by file name. The numbers are kept
+// as `json.Number`, like on the contexts decoded by the eval endpoint.
+func LoadCases(dir string) ([]*Case, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	cases := make([]*Case, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		c := &Case{}
+		err = decodeJSON(data, c)
+		if err != nil {
+			return nil, fmt.Errorf("error on decode %s: %w", path, err)
+		}
+
+		if c.Name == "" {
+			c.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// Run evaluates each case against the knowledge base with the resolvers stubbed and compares the got
+// result with the expected one.
+func Run(name string, evalService services.IEval, knowledgeBase *ast.KnowledgeBase, cases []*Case) *Report {
+	report := &Report{
+		Name:    name,
+		Results: make([]*CaseResult, 0, len(cases)),
+	}
+
+	for _, c := range cases {
+		report.Results = append(report.Results, runCase(evalService, knowledgeBase, c))
+	}
+	return report
+}
+
+// runCase evaluates a single case and compares its result.
+func runCase(evalService services.IEval, knowledgeBase *ast.KnowledgeBase, c *Case) *CaseResult {
+	values := make(map[string]interface{})
+	for key, value := range c.Context {
+		values[key] = value
+	}
+
+	ctx := types.NewContextFromMap(values)
+	// The resolvers are always stubbed, even when the case has no stubs, so the bridge is never called
+	ctx.Resolver = c.Resolvers
+
+	start := time.Now()
+	result, err := evalService.Eval(ctx, knowledgeBase)
+	caseResult := &CaseResult{
+		Case:     c,
+		Error:    err,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		return caseResult
+	}
+
+	expected := make(map[string]interface{})
+	for key, value := range c.Features {
+		expected[key] = value
+	}
+	if len(c.RequiredParamErrors) > 0 {
+		expected["requiredParamErrors"] = c.RequiredParamErrors
+	}
+
+	got, err := Normalize(result.GetFeatures())
+	if err != nil {
+		caseResult.Error = err
+		return caseResult
+	}
+
+	caseResult.Diffs = Diff(expected, got)
+	return caseResult
+}
+
+// Normalize converts the values to the same types they would have if decoded from JSON, with the numbers
+// as `json.Number`, so they can be compared with the expected values of a case without losing precision.
+func Normalize(values map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make(map[string]interface{})
+	err = decodeJSON(data, &normalized)
+	return normalized, err
+}
+
+// decodeJSON decodes the data keeping the numbers as `json.Number`.
+func decodeJSON(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// equalValues compares the values like reflect.DeepEqual, but the numbers by their value, so 52.5 and
+// 52.50 are equal while the number 1 and the string "1" aren't.
+func equalValues(a interface{}, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !equalValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equalValues(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if da, ok := toNumber(a); ok {
+		db, ok := toNumber(b)
+		return ok && da.Equal(db)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toNumber converts a JSON number, an int or a float to a decimal. The strings aren't numbers.
+func toNumber(value interface{}) (types.Decimal, bool) {
+	switch value.(type) {
+	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		d, err := types.ToDecimal(value)
+		return d, err == nil
+	}
+	return types.Decimal{}, false
+}
+
+// DiffKeys returns the keys whose value differs between the expected and the got maps, sorted.
+func DiffKeys(expected map[string]interface{}, got map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for key := range expected {
+		keys[key] = true
+	}
+	for key := range got {
+		keys[key] = true
+	}
+
+	diffKeys := []string{}
+	for key := range keys {
+		expectedValue, hasExpected := expected[key]
+		gotValue, hasGot := got[key]
+		if hasExpected != hasGot || !equalValues(expectedValue, gotValue) {
+			diffKeys = append(diffKeys, key)
+		}
+	}
+	sort.Strings(diffKeys)
+	return diffKeys
+}
+
+// Diff returns a description of each key whose value differs between the expected and the got maps,
+// sorted by key.
+func Diff(expected map[string]interface{}, got map[string]interface{}) []string {
+	diffs := []string{}
+	for _, key := range DiffKeys(expected, got) {
+		expectedValue, hasExpected := expected[key]
+		gotValue, hasGot := got[key]
+		switch {
+		case !hasGot:
+			diffs = append(diffs, fmt.Sprintf("%s: expected %s, but it's missing", key, toJSON(expectedValue)))
+		case !hasExpected:
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected %s", key, toJSON(gotValue)))
+		default:
+			diffs = append(diffs, fmt.Sprintf("%s: expected %s, got %s", key, toJSON(expectedValue), toJSON(gotValue)))
+		}
+	}
+	return diffs
+}
+
+// toJSON formats a value as JSON to be shown on the diffs.
+func toJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// WriteText writes a human readable report, with the diffs of each failed case.
+func (r *Report) WriteText(w io.Writer) {
+	for _, result := range r.Results {
+		if result.Passed() {
+			fmt.Fprintf(w, "--- PASS: %s (%.3fs)\n", result.Case.Name, result.Duration.Seconds())
+			continue
+		}
+
+		fmt.Fprintf(w, "--- FAIL: %s (%.3fs)\n", result.Case.Name, result.Duration.Seconds())
+		if result.Error != nil {
+			fmt.Fprintf(w, "    error: %v\n", result.Error)
+		}
+		for _, diff := range result.Diffs {
+			fmt.Fprintf(w, "    %s\n", diff)
+		}
+	}
+
+	status := "PASS"
+	if r.Failed() > 0 {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w, "%s %s: %d passed, %d failed\n", status, r.Name, len(r.Results)-r.Failed(), r.Failed())
+}
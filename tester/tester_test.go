@@ -0,0 +1,192 @@
+package tester
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/services"
+	"github.com/bancodobrasil/featws-ruller/types"
+	"github.com/hyperjumptech/grule-rule-engine/ast"
+)
+
+// testerGRL is a rulesheet with a remote loaded param and a required param used on the tests.
+const testerGRL = `
+	rule DefaultValues salience 10 {
+		when
+			true
+		then
+			ctx.RegistryRemoteLoaded("age", "customer");
+			ctx.RegistryRequiredParams("mynumber");
+			Retract("DefaultValues");
+	}
+
+	rule feat_adult salience 9 {
+		when
+			true
+		then
+			result.Put("adult", ctx.GetInt("age") >= 18);
+			result.Put("small", ctx.GetInt("mynumber") < 12);
+			Retract("feat_adult");
+	}
+`
+
+// loadTesterGRL builds the test rulesheet into a new evaluation service.
+func loadTesterGRL(t *testing.T) (services.IEval, *ast.KnowledgeBase) {
+	path := filepath.Join(t.TempDir(), "rules.grl")
+	err := os.WriteFile(path, []byte(testerGRL), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evalService := services.NewEval(config.GetConfig())
+	err = evalService.LoadLocalGRL(path, services.DefaultKnowledgeBaseName, services.DefaultKnowledgeBaseVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return evalService, evalService.GetDefaultKnowledgeBase()
+}
+
+// TestLoadCases checks if the cases are read from the JSON files of a directory, named by file.
+func TestLoadCases(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "02-named.json"), []byte(`{"name": "my case", "context": {"mynumber": 1}}`), 0644)
+	os.WriteFile(filepath.Join(dir, "01-adult.json"), []byte(`{"context": {"mynumber": 1}, "features": {"adult": true}}`), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte(`not a case`), 0644)
+
+	cases, err := LoadCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cases) != 2 || cases[0].Name != "01-adult" || cases[1].Name != "my case" {
+		t.Errorf("unexpected cases: %v", cases)
+	}
+
+	if got := cases[0].Context["mynumber"]; got != json.Number("1") {
+		t.Errorf("Test Fail, we want %v, we got %v", json.Number("1"), got)
+	}
+}
+
+// TestDiffNumbers checks if the numbers are compared by their value, without losing precision, and
+// not as the strings with the same digits.
+func TestDiffNumbers(t *testing.T) {
+	got, err := Normalize(map[string]interface{}{
+		"limit":  types.MustParseDecimal("52.50"),
+		"big":    types.MustParseDecimal("12345678901234567.89"),
+		"id":     1,
+		"counts": []interface{}{1, 2.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"limit":  json.Number("52.5"),
+		"big":    json.Number("12345678901234567.88"),
+		"id":     "1",
+		"counts": []interface{}{json.Number("1.0"), json.Number("2.50")},
+	}
+
+	keys := DiffKeys(expected, got)
+	if !reflect.DeepEqual(keys, []string{"big", "id"}) {
+		t.Errorf("Test Fail, we want %v, we got %v", []string{"big", "id"}, keys)
+	}
+}
+
+// TestRun checks if the passing and failing cases are reported with their diffs.
+func TestRun(t *testing.T) {
+	evalService, knowledgeBase := loadTesterGRL(t)
+
+	cases := []*Case{
+		{
+			Name:      "adult",
+			Context:   map[string]interface{}{"mynumber": 1},
+			Resolvers: map[string]map[string]interface{}{"customer": {"age": 21}},
+			Features:  map[string]interface{}{"adult": true, "small": true},
+		},
+		{
+			Name:      "missing param",
+			Resolvers: map[string]map[string]interface{}{"customer": {"age": 21}},
+			Features:  map[string]interface{}{"adult": true, "small": true},
+			RequiredParamErrors: map[string]interface{}{
+				"mynumber": []interface{}{"parameter mynumber is required"},
+			},
+		},
+		{
+			Name:      "wrong",
+			Context:   map[string]interface{}{"mynumber": 20},
+			Resolvers: map[string]map[string]interface{}{"customer": {"age": 10}},
+			Features:  map[string]interface{}{"adult": true, "small": false, "other": 1},
+		},
+	}
+
+	report := Run("rules.grl", evalService, knowledgeBase, cases)
+
+	if report.Failed() != 1 {
+		t.Fatalf("expected 1 failed case, got %d", report.Failed())
+	}
+
+	if !report.Results[0].Passed() || !report.Results[1].Passed() {
+		t.Errorf("expected the first cases to pass: %v %v", report.Results[0].Diffs, report.Results[1].Diffs)
+	}
+
+	expected := []string{
+		"adult: expected true, got false",
+		"other: expected 1, but it's missing",
+	}
+	if !reflect.DeepEqual(report.Results[2].Diffs, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, report.Results[2].Diffs)
+	}
+}
+
+// TestRunWithoutStub checks if a remote loaded param without stub is reported as an error of the result.
+func TestRunWithoutStub(t *testing.T) {
+	evalService, knowledgeBase := loadTesterGRL(t)
+
+	report := Run("rules.grl", evalService, knowledgeBase, []*Case{
+		{
+			Name:     "no stub",
+			Context:  map[string]interface{}{"mynumber": 1},
+			Features: map[string]interface{}{"adult": false, "small": true},
+		},
+	})
+
+	diffs := report.Results[0].Diffs
+	if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "errors: unexpected") {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+}
+
+// TestWriteJUnit checks if the report is written as a JUnit XML with the failures.
+func TestWriteJUnit(t *testing.T) {
+	report := &Report{
+		Name: "rules.grl",
+		Results: []*CaseResult{
+			{Case: &Case{Name: "ok"}},
+			{Case: &Case{Name: "ko"}, Diffs: []string{"adult: expected true, got false"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := report.WriteJUnit(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	for _, expected := range []string{
+		`<testsuite name="rules.grl" tests="2" failures="1"`,
+		`<testcase name="ok" classname="rules.grl" time="0.000"></testcase>`,
+		`<failure message="unexpected result">adult: expected true, got false</failure>`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected %s on the report:\n%s", expected, got)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bucketKey returns the representation of the key, without exponent on the floats, so the number 123
+// decoded from JSON and the string "123" are the same key.
+func bucketKey(key interface{}) string {
+	switch v := key.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// HashKey returns the stable hash of the key with the salt. The salt, usually the name of the feature
+// or of the experiment, makes the hashes of each one independent.
+func HashKey(key interface{}, salt string) uint32 {
+	return murmur3([]byte(salt+":"+bucketKey(key)), 0)
+}
+
+// SelectVariant selects a variant for the key, with a share of the keys proportional to the weight of
+// each variant. The variants are informed as "name:weight", like "control:50", and the same key always
+// gets the same variant of the same salt.
+func SelectVariant(key interface{}, salt string, variants ...string) (string, error) {
+	names := make([]string, len(variants))
+	weights := make([]float64, len(variants))
+	total := float64(0)
+
+	for i, variant := range variants {
+		separator := strings.LastIndex(variant, ":")
+		if separator < 0 {
+			return "", fmt.Errorf("invalid variant %s, it must be informed as name:weight", variant)
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(variant[separator+1:]), 64)
+		if err != nil || weight < 0 {
+			return "", fmt.Errorf("invalid weight of the variant %s", variant)
+		}
+
+		names[i] = strings.TrimSpace(variant[:separator])
+		weights[i] = weight
+		total += weight
+	}
+
+	if total <= 0 {
+		return "", fmt.Errorf("there isn't a variant with a positive weight")
+	}
+
+	point := float64(HashKey(key, salt)) / (1 << 32) * total
+	cumulative := float64(0)
+	last := 0
+	for i, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+		cumulative += weight
+		last = i
+		if point < cumulative {
+			return names[i], nil
+		}
+	}
+
+	// the rounding of the weights can leave the point after the last cumulative weight
+	return names[last], nil
+}
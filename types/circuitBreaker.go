@@ -0,0 +1,170 @@
+package types
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The states of a circuit breaker, also used as the values of the state metric.
+const (
+	CircuitBreakerClosed   = 0
+	CircuitBreakerHalfOpen = 1
+	CircuitBreakerOpen     = 2
+)
+
+// circuitBreakerState exports the state of the circuit breaker of each resolver.
+var circuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "featws_ruller_resolver_circuit_breaker_state",
+	Help: "State of the circuit breaker of the resolver: 0 closed, 1 half-open and 2 open.",
+}, []string{"resolver"})
+
+// resolverRetries counts the retried calls to the resolver bridge by resolver.
+var resolverRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "featws_ruller_resolver_retries_total",
+	Help: "Retried calls to the resolver bridge by resolver.",
+}, []string{"resolver"})
+
+// circuitBreaker stops calling the resolver bridge for a resolver after consecutive failures. After
+// the cooldown, a single call is allowed to check if the resolver recovered.
+//
+// Property:
+//   - mutex: protects the state of the breaker.
+//   - resolver: the name of the resolver.
+//   - state: the current state of the breaker.
+//   - failures: the number of consecutive failures.
+//   - openedAt: the moment the breaker was opened.
+type circuitBreaker struct {
+	mutex    sync.Mutex
+	resolver string
+	state    int
+	failures int64
+	openedAt time.Time
+}
+
+// allow verifies if a call to the resolver can be made. When the breaker is open and the cooldown is
+// over, it goes half-open and allows a single call.
+func (b *circuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case CircuitBreakerOpen:
+		cooldown := time.Duration(config.GetConfig().ResolverBreakerCooldown) * time.Second
+		if time.Since(b.openedAt) < cooldown {
+			return false
+		}
+		b.setState(CircuitBreakerHalfOpen)
+		return true
+	case CircuitBreakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// success records a successful call, closing the breaker.
+func (b *circuitBreaker) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = 0
+	b.setState(CircuitBreakerClosed)
+}
+
+// failure records a failed call, opening the breaker when the threshold of consecutive failures is
+// reached or when the half-open call fails.
+func (b *circuitBreaker) failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	threshold := config.GetConfig().ResolverBreakerThreshold
+	if b.state == CircuitBreakerHalfOpen || (threshold > 0 && b.failures >= threshold) {
+		b.openedAt = time.Now()
+		b.setState(CircuitBreakerOpen)
+	}
+}
+
+// canceled records a call canceled by the client, which says nothing about the resolver, so it isn't
+// a failure. A half-open breaker goes back to open, letting the next call check the resolver again.
+func (b *circuitBreaker) canceled() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == CircuitBreakerHalfOpen {
+		b.setState(CircuitBreakerOpen)
+	}
+}
+
+// breakerCall is a call allowed by a circuit breaker, whose outcome is recorded once.
+//
+// Property:
+//   - breaker: the circuit breaker of the resolver.
+//   - recorded: if the outcome of the call was recorded.
+type breakerCall struct {
+	breaker  *circuitBreaker
+	recorded bool
+}
+
+// call returns a call of the breaker, to be used after it's allowed.
+func (b *circuitBreaker) call() *breakerCall {
+	return &breakerCall{breaker: b}
+}
+
+// success records the call as successful, unless its outcome was already recorded.
+func (c *breakerCall) success() {
+	if !c.recorded {
+		c.recorded = true
+		c.breaker.success()
+	}
+}
+
+// failure records the call as failed, unless its outcome was already recorded.
+func (c *breakerCall) failure() {
+	if !c.recorded {
+		c.recorded = true
+		c.breaker.failure()
+	}
+}
+
+// canceled records the call as canceled by the client, unless its outcome was already recorded.
+func (c *breakerCall) canceled() {
+	if !c.recorded {
+		c.recorded = true
+		c.breaker.canceled()
+	}
+}
+
+// release records a failure when the call ended without an outcome, like on a panic before the
+// request is sent, so a half-open breaker doesn't wait forever for the result of its call.
+func (c *breakerCall) release() {
+	c.failure()
+}
+
+// setState changes the state of the breaker and its metric
+func (b *circuitBreaker) setState(state int) {
+	b.state = state
+	circuitBreakerState.WithLabelValues(b.resolver).Set(float64(state))
+}
+
+var circuitBreakersMutex sync.Mutex
+
+// circuitBreakers are the circuit breakers by resolver name, created on the first call to each resolver.
+var circuitBreakers = make(map[string]*circuitBreaker)
+
+// getCircuitBreaker returns the circuit breaker of a resolver, creating it if needed.
+func getCircuitBreaker(resolver string) *circuitBreaker {
+	circuitBreakersMutex.Lock()
+	defer circuitBreakersMutex.Unlock()
+
+	breaker, ok := circuitBreakers[resolver]
+	if !ok {
+		breaker = &circuitBreaker{resolver: resolver}
+		circuitBreakers[resolver] = breaker
+	}
+	return breaker
+}
@@ -0,0 +1,283 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	telemetry "github.com/bancodobrasil/gin-telemetry"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// MockHTTPClientFlaky is a mock of the resolver bridge that fails the first calls and then answers
+// every loaded param.
+//
+// Property:
+//   - failures: the number of calls that fail before the first success.
+//   - status: the status of the failed calls. When it's zero, the calls fail on the network.
+//   - calls: the number of received calls.
+type MockHTTPClientFlaky struct {
+	http.Client
+	failures int
+	status   int
+	calls    int
+}
+
+// Do fails while the number of calls is lower than the failures and then answers like MockHTTPClientBatch.
+func (m *MockHTTPClientFlaky) Do(req *http.Request) (*http.Response, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		if m.status == 0 {
+			return nil, fmt.Errorf("mock do error")
+		}
+		return &http.Response{
+			StatusCode: m.status,
+			Body:       io.NopCloser(strings.NewReader("unavailable")),
+		}, nil
+	}
+	return (&MockHTTPClientBatch{}).Do(req)
+}
+
+// setupResolverResilience configures the retries and the circuit breaker for a test and restores the
+// configuration at the end.
+func setupResolverResilience(t *testing.T, retries int64, threshold int64, cooldown int64) {
+	cfg := config.GetConfig()
+	cfg.ResolverRetries = retries
+	cfg.ResolverRetryBackoff = 1
+	cfg.ResolverBreakerThreshold = threshold
+	cfg.ResolverBreakerCooldown = cooldown
+	t.Cleanup(func() {
+		config.LoadConfig()
+	})
+}
+
+// TestResolverRetry checks if the failed calls are retried until the resolver answers.
+func TestResolverRetry(t *testing.T) {
+	setupResolverResilience(t, 2, 5, 30)
+
+	for _, status := range []int{0, http.StatusBadGateway} {
+		resolver := fmt.Sprintf("retryresolver%d", status)
+		client := &MockHTTPClientFlaky{failures: 2, status: status}
+		Client = client
+
+		ctx := NewContext()
+		ctx.RegistryRemoteLoaded("myparam", resolver)
+
+		if got := ctx.GetString("myparam"); got != "value of myparam" {
+			t.Errorf("Test Fail, we want %v, we got %v", "value of myparam", got)
+		}
+
+		if client.calls != 3 {
+			t.Errorf("expected 3 calls, got %d", client.calls)
+		}
+
+		if got := testutil.ToFloat64(resolverRetries.WithLabelValues(resolver)); got != 2 {
+			t.Errorf("expected 2 retries, got %v", got)
+		}
+	}
+}
+
+// TestResolverRetryClientError checks if the responses with a 4xx status aren't retried.
+func TestResolverRetryClientError(t *testing.T) {
+	setupResolverResilience(t, 2, 5, 30)
+
+	client := &MockHTTPClientFlaky{failures: 1, status: http.StatusBadRequest}
+	Client = client
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "clienterrorresolver")
+	ctx.load("myparam")
+
+	if client.calls != 1 {
+		t.Errorf("expected a single call, got %d", client.calls)
+	}
+}
+
+// TestCircuitBreakerOpen checks if the circuit breaker opens after the consecutive failures and fails
+// fast without calling the resolver bridge.
+func TestCircuitBreakerOpen(t *testing.T) {
+	setupResolverResilience(t, 0, 2, 30)
+
+	client := &MockHTTPClientFlaky{failures: 10}
+	Client = client
+
+	for i := 0; i < 3; i++ {
+		ctx := NewContext()
+		ctx.RegistryRemoteLoaded("myparam", "brokenresolver")
+		ctx.load("myparam")
+	}
+
+	if client.calls != 2 {
+		t.Errorf("expected 2 calls before opening the circuit breaker, got %d", client.calls)
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("brokenresolver")); got != CircuitBreakerOpen {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerOpen, got)
+	}
+}
+
+// TestCircuitBreakerHalfOpen checks if the circuit breaker allows a call after the cooldown and
+// closes when it succeeds.
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	setupResolverResilience(t, 0, 1, 0)
+
+	client := &MockHTTPClientFlaky{failures: 1}
+	Client = client
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "recoveredresolver")
+	ctx.load("myparam")
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("recoveredresolver")); got != CircuitBreakerOpen {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerOpen, got)
+	}
+
+	ctx = NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "recoveredresolver")
+
+	if got := ctx.GetString("myparam"); got != "value of myparam" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of myparam", got)
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("recoveredresolver")); got != CircuitBreakerClosed {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerClosed, got)
+	}
+}
+
+// MockHTTPClientContext is a mock of the resolver bridge that fails like a real client when the context
+// of the request is canceled, and otherwise answers every loaded param.
+type MockHTTPClientContext struct {
+	http.Client
+}
+
+// Do fails with the error of the context of the request when it's done.
+func (m *MockHTTPClientContext) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return (&MockHTTPClientBatch{}).Do(req)
+}
+
+// TestCircuitBreakerCanceled checks if the calls canceled by the client, with the `RawContext` canceled,
+// aren't failures of the resolver, so they never open the circuit breaker.
+func TestCircuitBreakerCanceled(t *testing.T) {
+	setupResolverResilience(t, 2, 1, 30)
+
+	telemetry.MiddlewareDisabled = true
+	defer func() {
+		telemetry.MiddlewareDisabled = false
+	}()
+
+	Client = &MockHTTPClientContext{}
+
+	rawCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 3; i++ {
+		ctx := NewContext()
+		ctx.RawContext = rawCtx
+		ctx.RegistryRemoteLoaded("myparam", "canceledresolver")
+
+		if got := ctx.GetEntry("myparam"); got != nil {
+			t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+		}
+		if got := ctx.GetMap("errors").GetSlice("myparam"); len(got) != 1 || got[0] != "the request was canceled" {
+			t.Errorf("Test Fail, we want %v, we got %v", "the request was canceled", got)
+		}
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("canceledresolver")); got != CircuitBreakerClosed {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerClosed, got)
+	}
+
+	if got := testutil.ToFloat64(resolverRetries.WithLabelValues("canceledresolver")); got != 0 {
+		t.Errorf("expected no retry of the canceled calls, got %v", got)
+	}
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "canceledresolver")
+	if got := ctx.GetString("myparam"); got != "value of myparam" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of myparam", got)
+	}
+}
+
+// TestCircuitBreakerHalfOpenCanceled checks if a half-open call canceled by the client lets the next
+// call check the resolver again.
+func TestCircuitBreakerHalfOpenCanceled(t *testing.T) {
+	setupResolverResilience(t, 0, 1, 0)
+
+	telemetry.MiddlewareDisabled = true
+	defer func() {
+		telemetry.MiddlewareDisabled = false
+	}()
+
+	Client = &MockHTTPClientFlaky{failures: 1}
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "halfopencanceledresolver")
+	ctx.load("myparam")
+
+	Client = &MockHTTPClientContext{}
+	rawCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx = NewContext()
+	ctx.RawContext = rawCtx
+	ctx.RegistryRemoteLoaded("myparam", "halfopencanceledresolver")
+	ctx.load("myparam")
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("halfopencanceledresolver")); got != CircuitBreakerOpen {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerOpen, got)
+	}
+
+	ctx = NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "halfopencanceledresolver")
+	if got := ctx.GetString("myparam"); got != "value of myparam" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of myparam", got)
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("halfopencanceledresolver")); got != CircuitBreakerClosed {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerClosed, got)
+	}
+}
+
+// TestCircuitBreakerHalfOpenReleased checks if a half-open call that fails before reaching the resolver,
+// like on an invalid request, is recorded as a failure instead of keeping the breaker half-open.
+func TestCircuitBreakerHalfOpenReleased(t *testing.T) {
+	setupResolverResilience(t, 0, 1, 0)
+
+	Client = &MockHTTPClientFlaky{failures: 1}
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "halfopenreleasedresolver")
+	ctx.load("myparam")
+
+	url := config.GetConfig().ResolverBridgeURL
+	config.GetConfig().ResolverBridgeURL = "http://[invalid"
+
+	ctx = NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "halfopenreleasedresolver")
+	ctx.load("myparam")
+
+	if got := ctx.GetMap("errors").GetSlice("myparam"); len(got) != 1 || got[0] != "error on create Request" {
+		t.Errorf("Test Fail, we want %v, we got %v", "error on create Request", got)
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("halfopenreleasedresolver")); got != CircuitBreakerOpen {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerOpen, got)
+	}
+
+	config.GetConfig().ResolverBridgeURL = url
+
+	ctx = NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "halfopenreleasedresolver")
+	if got := ctx.GetString("myparam"); got != "value of myparam" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of myparam", got)
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("halfopenreleasedresolver")); got != CircuitBreakerClosed {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerClosed, got)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bancodobrasil/featws-ruller/config"
 	telemetry "github.com/bancodobrasil/gin-telemetry"
@@ -20,9 +22,13 @@ import (
 // Property:
 //   - Resolver: is a string property that represents the URL or IP address of the remote server that is being used to resolve a particular resource or service. It is often used in distributed systems or client-server architectures where different components need to communicate with each other over a network.
 //   - From - The "From" property is a string that represents the source of the remote loaded resource. It could be a URL or a file path, for example.
+//   - Policy: the FailurePolicy applied when the resolver fails to load the param.
+//   - Fallback: the value of the param when the resolver fails and the policy is FailurePolicyFallback.
 type RemoteLoaded struct {
 	Resolver string
 	From     string
+	Policy   FailurePolicy
+	Fallback interface{}
 }
 
 // RemoteLoadeds is a map with string keys and values of type `RemoteLoaded`. `RemoteLoaded` is likely a
@@ -43,6 +49,10 @@ type RemoteLoadeds map[string]RemoteLoaded
 //   - Resolver  - 1. `RawContext`: This is a context.Context object that is used to carry deadlines, cancellation signals, and other request-scoped values across API boundaries and between processes.
 //   - Loader  - 1. `RawContext`: This is a context.Context object that is used to carry deadlines, cancellation signals, and other request-scoped values across API boundaries and between processes.
 //   - RequiredConfigured: is a boolean property that indicates whether all the required parameters and configurations have been set for the context. If it is set to `true`, it means that all the necessary parameters and configurations have been provided and the context is ready to be used. `
+//   - BypassResolverCache: indicates whether the remote loaded params must be resolved by the resolver bridge even if their responses are cached.
+//   - ResolverContextKeys: the context keys declared by resolver name. Only these keys are sent to a resolver with declared keys.
+//   - failure: the error of a remote loaded param with the fail policy, which fails the whole evaluation.
+//   - failed: the remote loaded params whose resolver failed, after their failure policies were applied, so they aren't loaded again.
 type Context struct {
 	RawContext context.Context
 	TypedMap
@@ -50,7 +60,11 @@ type Context struct {
 	RequiredParams []string
 	Resolver
 	Loader
-	RequiredConfigured bool
+	RequiredConfigured  bool
+	BypassResolverCache bool
+	ResolverContextKeys map[string][]string
+	failure             *RemoteLoadError
+	failed              map[string]bool
 }
 
 // Resolver defines an interface for resolving a parameter using a resolver.
@@ -61,6 +75,16 @@ type Resolver interface {
 	resolve(resolver string, param string) interface{}
 }
 
+// BatchResolver is an optional interface of a Resolver that resolves several params of the same resolver
+// at once. When the Resolver of a Context implements it, the pending remote loaded params of a resolver
+// are resolved together on the first access of any of them.
+//
+// Property:
+//   - resolveBatch: takes the name of the resolver and the params to be resolved, and returns the resolved values by param.
+type BatchResolver interface {
+	resolveBatch(resolver string, params []string) map[string]interface{}
+}
+
 // Loader is an interface with a method signature for loading data.
 //
 // Property:
@@ -111,6 +135,75 @@ func NewContextFromMap(values map[string]interface{}) *Context {
 	return instance
 }
 
+// resolvedResolver is the Resolver of the contexts built from the entries of another evaluation, which
+// never calls the resolvers again.
+//
+// Property:
+//   - errors: the errors recorded by the other evaluation by param, raised again for the params that failed.
+//   - unresolved: the params that the other evaluation didn't load, in the order of the first access.
+type resolvedResolver struct {
+	errors     map[string]interface{}
+	mutex      sync.Mutex
+	unresolved []string
+}
+
+// resolve fails the param, with the first error recorded by the other evaluation when there is one, or
+// as unresolved.
+func (r *resolvedResolver) resolve(resolver string, param string) interface{} {
+	if errs, ok := r.errors[param].([]interface{}); ok && len(errs) > 0 {
+		log.Panic(fmt.Sprintf("%v", errs[0]))
+	}
+
+	r.mutex.Lock()
+	found := false
+	for _, unresolved := range r.unresolved {
+		found = found || unresolved == param
+	}
+	if !found {
+		r.unresolved = append(r.unresolved, param)
+	}
+	r.mutex.Unlock()
+
+	log.Panicf("the param %s of the resolver %s wasn't resolved", param, resolver)
+	return nil
+}
+
+// NewResolvedContextFromMap creates a Context from the entries of another evaluation, like the shadow
+// evaluation of a served request. The remote loaded params are never resolved again: the loaded ones
+// are used as they are, and the others fail with the error recorded by the other evaluation, or as
+// unresolved, applying their failure policies. The "errors" and "requiredParamErrors" of the other
+// evaluation are left out, since the evaluation records its own.
+func NewResolvedContextFromMap(entries map[string]interface{}) *Context {
+	values := make(map[string]interface{}, len(entries))
+	for key, value := range entries {
+		values[key] = value
+	}
+	delete(values, "requiredParamErrors")
+	delete(values, "errors")
+
+	errors := map[string]interface{}{}
+	if errs, ok := toTypedMap(entries["errors"]); ok {
+		errors = errs.(*TypedMap).GetEntries()
+	}
+
+	instance := NewContextFromMap(values)
+	instance.Resolver = &resolvedResolver{errors: errors}
+	return instance
+}
+
+// UnresolvedParams returns the remote loaded params of a context built with `NewResolvedContextFromMap`
+// that the other evaluation didn't load, so they failed as unresolved. It's empty on the other contexts.
+func (c *Context) UnresolvedParams() []string {
+	resolver, ok := c.Resolver.(*resolvedResolver)
+	if !ok {
+		return []string{}
+	}
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+	return append([]string{}, resolver.unresolved...)
+}
+
 // RegistryRequiredParams have a struct called `Context`. This method takes in a variable number of string parameters and checks if each parameter is loaded
 // remotely. If a parameter is not loaded remotely, it is added to the `RequiredParams` slice of the
 // `Context` struct. Additionally, if the parameter is not present in the `Context`, an error is added
@@ -137,17 +230,21 @@ func (c *Context) addError(key, param string, err interface{}) {
 		c.Put(key, NewTypedMap())
 	}
 
+	// GetEntries turns the errors into a plain map, so they're stored again as a TypedMap
+	errs := c.GetMap(key)
+	c.Put(key, errs)
+
 	switch r := err.(type) {
 	case *log.Entry:
-		c.GetMap(key).AddItem(param, r.Message)
+		errs.AddItem(param, r.Message)
 	case log.Entry:
-		c.GetMap(key).AddItem(param, r.Message)
+		errs.AddItem(param, r.Message)
 	case string:
-		c.GetMap(key).AddItem(param, r)
+		errs.AddItem(param, r)
 	case error:
-		c.GetMap(key).AddItem(param, r.Error())
+		errs.AddItem(param, r.Error())
 	default:
-		c.GetMap(key).AddItem(param, fmt.Sprintf("%v", r))
+		errs.AddItem(param, fmt.Sprintf("%v", r))
 	}
 }
 
@@ -177,6 +274,24 @@ func (c *Context) RegistryRemoteLoaded(param string, resolver string) {
 	c.RegistryRemoteLoadedWithFrom(param, resolver, "")
 }
 
+// RegistryRemoteLoadedWithFallback registers a remote loaded param that assumes the fallback value
+// when its resolver fails. The failure is still recorded under "errors".
+func (c *Context) RegistryRemoteLoadedWithFallback(param string, resolver string, fallback interface{}) {
+	c.RegistryRemoteLoadedWithPolicy(param, resolver, "", string(FailurePolicyFallback), fallback)
+}
+
+// RegistryRemoteLoadedWithPolicy registers a remote loaded param with the policy applied when its
+// resolver fails: "fallback" uses the fallback value, "fail" fails the whole evaluation and "required"
+// records the param as a missing required param. An empty policy only records the failure under
+// "errors", like RegistryRemoteLoaded.
+func (c *Context) RegistryRemoteLoadedWithPolicy(param string, resolver string, from string, policy string, fallback interface{}) {
+	c.RegistryRemoteLoadedWithFrom(param, resolver, from)
+	remote := c.RemoteLoadeds[param]
+	remote.Policy = FailurePolicy(policy)
+	remote.Fallback = fallback
+	c.RemoteLoadeds[param] = remote
+}
+
 // The `load` function is a method defined on the `Context` struct. It is responsible for loading a parameter
 // from a remote resolver and storing it in the `TypedMap` field of the `Context` struct.
 func (c *Context) load(param string) interface{} {
@@ -188,25 +303,60 @@ func (c *Context) load(param string) interface{} {
 
 // The `loadImpl` function is a method defined on the `Context` struct in the Go programming language.
 // It is responsible for loading a parameter from a remote resolver and storing it in the `TypedMap`
-// field of the `Context` struct.
-func (c *Context) loadImpl(param string) interface{} {
+// field of the `Context` struct. When the resolver can be called in batch, all the pending remote loaded
+// params of the same resolver are loaded together with the requested one, in a single call. When the
+// resolver fails, or fails to resolve just some of the params, the failure policy of each failed param
+// is applied.
+func (c *Context) loadImpl(param string) (value interface{}) {
+	remote, ok := c.RemoteLoadeds[param]
 	defer func() {
 		r := recover()
 		if r == nil {
 			return
 		}
-		c.addError("errors", param, r)
+		if !ok {
+			c.addError("errors", param, r)
+			return
+		}
+		value = c.applyFailurePolicy(param, remote, r)
 	}()
-	remote, ok := c.RemoteLoadeds[param]
 	if !ok {
 		log.Panic("The param it's not registry as remote loaded")
 	}
 
-	value := c.resolve(remote.Resolver, remote.From)
-	c.Put(param, value)
+	params := map[string]RemoteLoaded{param: remote}
+	if c.canResolveBatch() {
+		params = c.pendingRemoteLoadeds(remote.Resolver)
+		params[param] = remote
+	}
+
+	values := c.resolveAll(remote.Resolver, remoteLoadedFroms(params))
+	value = values[remote.From]
+	for p, r := range params {
+		failure, failed := values[r.From].(*paramError)
+		if !failed {
+			c.Put(p, values[r.From])
+			continue
+		}
+		fallback := c.applyFailurePolicy(p, r, failure.message)
+		if p == param {
+			value = fallback
+		}
+	}
 	return value
 }
 
+// pendingRemoteLoadeds returns the remote loaded params of a resolver that weren't loaded yet.
+func (c *Context) pendingRemoteLoadeds(resolver string) map[string]RemoteLoaded {
+	pending := make(map[string]RemoteLoaded)
+	for param, remote := range c.RemoteLoadeds {
+		if remote.Resolver == resolver && c.TypedMap.GetEntry(param) == nil && !c.failed[param] {
+			pending[param] = remote
+		}
+	}
+	return pending
+}
+
 // The `isRemoteLoaded` method is a function defined on the `Context` struct in the Go programming
 // language. It takes a single parameter `param` of type `string` and returns a boolean value. The
 // purpose of this method is to check whether a given parameter is registered as a remote loaded
@@ -225,7 +375,7 @@ func (c *Context) isRemoteLoaded(param string) bool {
 func (c *Context) GetEntry(param string) interface{} {
 	value := c.TypedMap.GetEntry(param)
 
-	if value == nil && c.isRemoteLoaded(param) {
+	if value == nil && c.isRemoteLoaded(param) && !c.failed[param] {
 		return c.load(param)
 	}
 
@@ -271,21 +421,81 @@ func (c *Context) resolve(resolver string, param string) interface{} {
 	return c.resolveImpl(resolver, param)
 }
 
+// canResolveBatch verifies if several params of a resolver can be resolved in a single call, that is
+// the case of the resolver bridge and of the Resolvers implementing the `BatchResolver` interface.
+func (c *Context) canResolveBatch() bool {
+	if c.Resolver == nil {
+		return true
+	}
+	_, ok := c.Resolver.(BatchResolver)
+	return ok
+}
+
+// resolveAll resolves several params of the same resolver and returns their values by param. It calls
+// the `BatchResolver` or the resolver bridge once for all the params, or falls back to resolve each
+// param with the `Resolver` of the context. Without a `Resolver` on the context, the in-process
+// resolver registered with the name, or the fixture of the resolver on the fixture mode, is used
+// before the resolver bridge.
+func (c *Context) resolveAll(resolver string, params []string) map[string]interface{} {
+	var entries map[string]interface{}
+	if c.Resolver == nil {
+		entries = c.GetEntries()
+	}
+	return c.resolveAllFrom(entries, resolver, params)
+}
+
+// resolveAllFrom works like `resolveAll`, but sends the given entries as the context of the resolver
+// bridge, so it doesn't touch the entries of the context and can run concurrently.
+func (c *Context) resolveAllFrom(entries map[string]interface{}, resolver string, params []string) map[string]interface{} {
+	if c.Resolver == nil {
+		if plugin, ok := getInProcessResolver(resolver); ok {
+			return resolveAllWithPlugin(plugin, entries, resolver, params)
+		}
+		return c.resolveCachedImpl(resolver, c.resolverEntries(resolver, entries), params)
+	}
+
+	if batch, ok := c.Resolver.(BatchResolver); ok {
+		return batch.resolveBatch(resolver, params)
+	}
+
+	values := make(map[string]interface{})
+	for _, param := range params {
+		values[param] = c.Resolver.resolve(resolver, param)
+	}
+	return values
+}
+
 // The `resolveImpl` function is responsible for resolving a parameter using a remote resolver. It
 // constructs a request to the resolver bridge API, sends the request, and returns the resolved value.
 // The function takes two arguments: `resolver` and `param`, where `resolver` is the name of the
 // resolver to use and `param` is the name of the parameter to resolve.
 func (c *Context) resolveImpl(resolver string, param string) interface{} {
-	config := config.GetConfig()
+	if plugin, ok := getInProcessResolver(resolver); ok {
+		return resolveAllWithPlugin(plugin, c.GetEntries(), resolver, []string{param})[param]
+	}
+	return c.resolveCachedImpl(resolver, c.resolverEntries(resolver, c.GetEntries()), []string{param})[param]
+}
 
-	url := fmt.Sprintf("%s/api/v1/resolve/%s", config.ResolverBridgeURL, resolver)
+// The `resolveAllImpl` function constructs a single request to the resolver bridge API loading all the
+// params with the given entries as context, sends the request, over HTTP or gRPC by the transport of the
+// route of the resolver, and returns the resolved values by param. On the v2 protocol, it also returns the max-age hints by param, and the params that failed while the
+// others succeeded are returned as a `paramError`.
+func (c *Context) resolveAllImpl(resolver string, entries map[string]interface{}, params []string) (map[string]interface{}, map[string]time.Duration) {
+	bridge := config.GetConfig().ResolverBridgeFor(resolver)
+
+	protocol := bridge.Protocol
+	if protocol == "" {
+		protocol = ResolverProtocolV1
+	}
 
-	url = strings.ReplaceAll(url, "//api/v1", "/api/v1")
+	url := fmt.Sprintf("%s/api/%s/resolve/%s", bridge.URL, protocol, resolver)
+
+	url = strings.ReplaceAll(url, "//api/"+protocol, "/api/"+protocol)
 
 	input := resolveInputV1{
 		// Resolver: resolver,
-		Context: c.GetEntries(),
-		Load:    []string{param},
+		Context: entries,
+		Load:    params,
 	}
 
 	log.Tracef("Resolving with '%s': %v", url, input)
@@ -299,61 +509,161 @@ func (c *Context) resolveImpl(resolver string, param string) interface{} {
 
 	log.Tracef("Resolving with '%s' decoded: %v", url, buf.String())
 
-	ctx := c.RawContext
-	var req *http.Request
-
-	if ctx != nil {
-		req, err = http.NewRequestWithContext(ctx, "POST", url, &buf)
-	} else {
-		req, err = http.NewRequest("POST", url, &buf)
+	breaker := getCircuitBreaker(resolver)
+	if !breaker.allow() {
+		log.WithField("resolver", resolver).Panic("the circuit breaker of the resolver is open")
 	}
+	call := breaker.call()
+	defer call.release()
+
+	if bridge.Transport == config.ResolverTransportGRPC {
+		normalized := resolveInputV1{}
+		err = json.Unmarshal(buf.Bytes(), &normalized)
+		if err != nil {
+			log.WithError(err).Panic("error on encode input")
+		}
 
-	if err != nil {
-		log.WithError(err).Panic("error on create Request")
+		output := c.resolveAllGRPC(resolver, bridge, normalized, call)
+		return output.values(), nil
 	}
 
-	req.Header = config.ResolverBridgeHeaders
-
-	if !telemetry.MiddlewareDisabled && ctx != nil {
-		telemetry.Inject(ctx, req.Header)
-	}
+	data := c.doResolverRequest(resolver, bridge, url, buf.Bytes(), call)
+	log.Tracef("Resolving with '%s': %v > %s", url, input, string(data))
 
-	resp, err := Client.Do(req)
-	if err != nil {
-		log.WithError(err).Panic("error on execute request")
-	}
-	defer resp.Body.Close()
+	if protocol == ResolverProtocolV2 {
+		output, err := decodeOutputV2(data)
+		if err != nil {
+			call.failure()
+			log.WithError(err).Panic("error on response decoding")
+		}
+		call.success()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.WithError(err).Panic("error on read the body")
+		return output.decode(params)
 	}
 
-	log.Tracef("Resolving with '%s': %v > %s", url, input, string(data))
-
 	output := resolveOutputV1{}
 	err = json.Unmarshal(data, &output)
 	if err != nil {
+		call.failure()
 		log.WithError(err).Panic("error on response decoding")
 	}
+	call.success()
+
+	return output.values(), nil
+}
+
+// values returns the resolved values by param of the response, panicking if the response has errors.
+func (o *resolveOutputV1) values() map[string]interface{} {
+	if len(o.Errors) > 0 {
+		log.WithField("errors", o.Errors).Panic(fmt.Sprintf("%s", o.Errors))
+	}
+
+	if o.Error != "" {
+		log.WithField("error", o.Error).Panic(o.Error)
+	}
 
-	if len(output.Errors) > 0 {
-		log.WithField("errors", output.Errors).Panic(fmt.Sprintf("%s", output.Errors))
+	if o.Context == nil {
+		o.Context = make(map[string]interface{})
 	}
 
-	if output.Error != "" {
-		log.WithField("error", output.Error).Panic(output.Error)
+	return o.Context
+}
+
+// doResolverRequest sends the encoded input to the resolver bridge and returns the body of the response.
+// Each attempt is limited by the timeout of the route of the resolver, and the network failures and the 5xx
+// responses are retried with exponential backoff while the `RawContext` isn't canceled. The failures
+// are recorded on the circuit breaker of the resolver, except the ones caused by the cancellation of the
+// `RawContext`, like a client that disconnected.
+func (c *Context) doResolverRequest(resolver string, bridge *config.ResolverBridge, url string, body []byte, call *breakerCall) []byte {
+	config := config.GetConfig()
+
+	ctx := c.RawContext
+	parent := ctx
+	if parent == nil {
+		parent = context.Background()
 	}
 
-	return output.Context[param]
+	timeout := bridge.Timeout
+
+	backoff := time.Duration(config.ResolverRetryBackoff) * time.Millisecond
+
+	for attempt := int64(0); ; attempt++ {
+		reqCtx, cancel := parent, context.CancelFunc(func() {})
+		if timeout > 0 {
+			reqCtx, cancel = context.WithTimeout(parent, timeout)
+		}
+
+		req, err := http.NewRequestWithContext(reqCtx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			cancel()
+			log.WithError(err).Panic("error on create Request")
+		}
+
+		req.Header = bridge.Headers.Clone()
+
+		if !telemetry.MiddlewareDisabled && ctx != nil {
+			telemetry.Inject(ctx, req.Header)
+		}
+
+		resp, err := Client.Do(req)
+
+		retryable := (err != nil || resp.StatusCode >= http.StatusInternalServerError) && parent.Err() == nil
+		if retryable && attempt < config.ResolverRetries {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			cancel()
+			log.WithField("resolver", resolver).Debugf("retrying the resolver request, attempt %d", attempt+1)
+			resolverRetries.WithLabelValues(resolver).Inc()
+
+			select {
+			case <-parent.Done():
+			case <-time.After(backoff << attempt):
+			}
+			continue
+		}
+
+		if err != nil {
+			cancel()
+			if parent.Err() != nil {
+				call.canceled()
+				log.WithError(err).Panic("the request was canceled")
+			}
+			call.failure()
+			log.WithError(err).Panic("error on execute request")
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		if err != nil {
+			if parent.Err() != nil {
+				call.canceled()
+				log.WithError(err).Panic("the request was canceled")
+			}
+			call.failure()
+			log.WithError(err).Panic("error on read the body")
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			call.failure()
+			log.WithField("body", string(data)).Panicf("the resolver bridge returned the status %d", resp.StatusCode)
+		}
+
+		return data
+	}
 }
 
 // SetRequiredConfigured sets the `RequiredConfigured` field of the `Context` struct to `true`. This field is
 // used to keep track of whether all the required parameters have been configured or not. When this
 // field is set to `true`, it means that all the required parameters have been configured and the
-// context is ready to be used.
+// context is ready to be used. When `FEATWS_RULLER_RESOLVER_PREFETCH` is enabled, the remote loaded
+// params registered so far are prefetched too.
 func (c *Context) SetRequiredConfigured() {
 	c.RequiredConfigured = true
+	if config.GetConfig().ResolverPrefetch {
+		c.Prefetch()
+	}
 }
 
 // IsReady returns a boolean value indicating whether the context is ready or not. A context is
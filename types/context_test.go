@@ -712,3 +712,179 @@ func TestResolveUnexpectedError(t *testing.T) {
 }
 
 // TestResolve stop
+
+// MockHTTPClientBatch is a mock of the resolver bridge that answers every loaded param and records the
+// loads of each call.
+//
+// Property:
+//   - loads: the params loaded on each call, in the order of the calls.
+type MockHTTPClientBatch struct {
+	http.Client
+	loads [][]string
+}
+
+// Do records the loaded params and answers each one of them with its name prefixed by "value of".
+func (m *MockHTTPClientBatch) Do(req *http.Request) (*http.Response, error) {
+	input := resolveInputV1{}
+	err := json.NewDecoder(req.Body).Decode(&input)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	m.loads = append(m.loads, input.Load)
+
+	output := resolveOutputV1{Context: map[string]interface{}{}}
+	for _, param := range input.Load {
+		output.Context[param] = "value of " + param
+	}
+
+	data, _ := json.Marshal(output)
+
+	return &http.Response{
+		Body: io.NopCloser(strings.NewReader(string(data))),
+	}, nil
+}
+
+// TestLoadBatch checks if the pending remote loaded params of a resolver are loaded in a single call on
+// the first access of any of them.
+func TestLoadBatch(t *testing.T) {
+	client := &MockHTTPClientBatch{}
+	Client = client
+
+	ctx := NewContext()
+	ctx.Put("informed", "myvalue")
+	ctx.RegistryRemoteLoaded("first", "myresolver")
+	ctx.RegistryRemoteLoadedWithFrom("second", "myresolver", "myfrom")
+	ctx.RegistryRemoteLoaded("informed", "myresolver")
+	ctx.RegistryRemoteLoaded("other", "otherresolver")
+
+	if got := ctx.GetString("second"); got != "value of myfrom" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of myfrom", got)
+	}
+
+	if got := ctx.GetString("first"); got != "value of first" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of first", got)
+	}
+
+	if got := ctx.GetString("informed"); got != "myvalue" {
+		t.Errorf("Test Fail, we want %v, we got %v", "myvalue", got)
+	}
+
+	expectedLoads := [][]string{{"first", "myfrom"}}
+	if !reflect.DeepEqual(client.loads, expectedLoads) {
+		t.Errorf("Test Fail, we want %v, we got %v", expectedLoads, client.loads)
+	}
+
+	if got := ctx.GetString("other"); got != "value of other" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of other", got)
+	}
+
+	if len(client.loads) != 2 {
+		t.Errorf("expected a call for the other resolver, got %v", client.loads)
+	}
+}
+
+// TestNewResolvedContextFromMap checks if the context built from the entries of another evaluation
+// reuses its loaded params and never calls the resolver bridge again.
+func TestNewResolvedContextFromMap(t *testing.T) {
+	client := &MockHTTPClientBatch{}
+	Client = client
+
+	ctx := NewResolvedContextFromMap(map[string]interface{}{
+		"loaded":              "myvalue",
+		"errors":              map[string]interface{}{"failed": []interface{}{"failed unavailable"}},
+		"requiredParamErrors": map[string]interface{}{"other": []interface{}{"parameter other is required"}},
+	})
+	ctx.RegistryRemoteLoaded("loaded", "myresolver")
+	ctx.RegistryRemoteLoadedWithFallback("failed", "myresolver", "myfallback")
+	ctx.RegistryRemoteLoaded("unresolved", "myresolver")
+
+	if got := ctx.GetString("loaded"); got != "myvalue" {
+		t.Errorf("Test Fail, we want %v, we got %v", "myvalue", got)
+	}
+
+	if got := ctx.GetString("failed"); got != "myfallback" {
+		t.Errorf("Test Fail, we want %v, we got %v", "myfallback", got)
+	}
+
+	if got := ctx.GetEntry("unresolved"); got != nil {
+		t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+	}
+
+	expected := map[string]interface{}{
+		"failed":     []interface{}{"failed unavailable"},
+		"unresolved": []interface{}{"the param unresolved of the resolver myresolver wasn't resolved"},
+	}
+	if got := ctx.GetMap("errors").GetEntries(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	if ctx.Has("requiredParamErrors") {
+		t.Error("expected the required param errors of the other evaluation to be left out")
+	}
+
+	if got := ctx.UnresolvedParams(); !reflect.DeepEqual(got, []string{"unresolved"}) {
+		t.Errorf("Test Fail, we want %v, we got %v", []string{"unresolved"}, got)
+	}
+
+	if len(client.loads) != 0 {
+		t.Errorf("expected no call to the resolver bridge, got %v", client.loads)
+	}
+}
+
+// MockHTTPClientRouting is a mock of the resolver bridges that records the requests and answers like
+// MockHTTPClientBatch.
+//
+// Property:
+//   - requests: the received requests, in the order of the calls.
+type MockHTTPClientRouting struct {
+	http.Client
+	requests []*http.Request
+}
+
+// Do records the request and answers each loaded param with its name prefixed by "value of".
+func (m *MockHTTPClientRouting) Do(req *http.Request) (*http.Response, error) {
+	m.requests = append(m.requests, req)
+	return (&MockHTTPClientBatch{}).Do(req)
+}
+
+// TestResolverBridgeRouting checks if each resolver is sent to the resolver bridge of its route, with
+// its headers, and the others to the default one.
+func TestResolverBridgeRouting(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.ResolverBridgeURL = "http://default"
+	cfg.ResolverBridges = []*config.ResolverBridge{
+		{Name: "cards", Match: []string{"cards-*"}, URL: "http://cards", Headers: http.Header{"X-Team": []string{"cards"}}},
+		{Name: "accounts", Match: []string{"account"}, URL: "http://accounts", Headers: http.Header{}},
+	}
+	defer config.LoadConfig()
+
+	client := &MockHTTPClientRouting{}
+	Client = client
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("limit", "cards-limit")
+	ctx.RegistryRemoteLoaded("balance", "account")
+	ctx.RegistryRemoteLoaded("age", "customer")
+	ctx.GetEntry("limit")
+	ctx.GetEntry("balance")
+	ctx.GetEntry("age")
+
+	expected := []string{
+		"http://cards/api/v1/resolve/cards-limit",
+		"http://accounts/api/v1/resolve/account",
+		"http://default/api/v1/resolve/customer",
+	}
+	if len(client.requests) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(client.requests))
+	}
+	for i, req := range client.requests {
+		if req.URL.String() != expected[i] {
+			t.Errorf("Test Fail, we want %v, we got %v", expected[i], req.URL.String())
+		}
+	}
+
+	if got := client.requests[0].Header.Get("X-Team"); got != "cards" {
+		t.Errorf("Test Fail, we want %v, we got %v", "cards", got)
+	}
+}
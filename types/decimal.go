@@ -0,0 +1,353 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode is the way a decimal is rounded when it loses digits.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds to the nearest digit, and the ties away from zero.
+	RoundHalfUp RoundingMode = "HALF_UP"
+	// RoundHalfDown rounds to the nearest digit, and the ties towards zero.
+	RoundHalfDown RoundingMode = "HALF_DOWN"
+	// RoundHalfEven rounds to the nearest digit, and the ties to the even digit, like the banker's rounding.
+	RoundHalfEven RoundingMode = "HALF_EVEN"
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = "UP"
+	// RoundDown rounds towards zero, truncating the digits.
+	RoundDown RoundingMode = "DOWN"
+	// RoundCeiling rounds towards the positive infinity.
+	RoundCeiling RoundingMode = "CEILING"
+	// RoundFloor rounds towards the negative infinity.
+	RoundFloor RoundingMode = "FLOOR"
+)
+
+// Decimal is an arbitrary-precision decimal number, used for the money amounts that can't lose
+// precision like the floats do. It's immutable and its zero value is zero.
+//
+// Property:
+//   - value: the unscaled value of the decimal.
+//   - scale: the number of digits after the decimal point.
+type Decimal struct {
+	value *big.Int
+	scale int32
+}
+
+// NewDecimalFromInt creates a decimal of an int.
+func NewDecimalFromInt(value int64) Decimal {
+	return Decimal{value: big.NewInt(value)}
+}
+
+// NewDecimalFromFloat creates a decimal of the shortest representation of a float, so 0.1 is exactly
+// 0.1 instead of its binary approximation.
+func NewDecimalFromFloat(value float64) (Decimal, error) {
+	return ParseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// maxDecimalExponent is the greatest exponent and scale of a parsed decimal, and the greatest scale of
+// a division or a rounding, so an input like "1e-2147483648" can't overflow the scale or allocate a
+// huge value.
+const maxDecimalExponent = 1000
+
+// ParseDecimal parses a decimal from a string, like "-1234.56" or "1.5e3". The exponent and the
+// resulting scale are limited to 1000 digits.
+func ParseDecimal(str string) (Decimal, error) {
+	str = strings.TrimSpace(str)
+
+	exponent := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		var err error
+		exponent, err = strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil || exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("invalid decimal %s", str)
+		}
+		str = str[:i]
+	}
+
+	digits := str
+	scale := int64(0)
+	if i := strings.Index(str, "."); i >= 0 {
+		digits = str[:i] + str[i+1:]
+		scale = int64(len(str) - i - 1)
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %s", str)
+	}
+
+	scale -= exponent
+	if scale > maxDecimalExponent || scale < -maxDecimalExponent {
+		return Decimal{}, fmt.Errorf("the scale of the decimal %s is out of range", str)
+	}
+	if scale < 0 {
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal parses a decimal like ParseDecimal, panicking when it's invalid.
+func MustParseDecimal(str string) Decimal {
+	d, err := ParseDecimal(str)
+	if err != nil {
+		panic(err.Error())
+	}
+	return d
+}
+
+// pow10 returns 10 raised to the exponent.
+func pow10(exponent int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(exponent), nil)
+}
+
+// unscaled returns the unscaled value, that is nil on the zero value of Decimal.
+func (d Decimal) unscaled() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// rescale returns the unscaled value of the decimal on a greater scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	value := new(big.Int).Set(d.unscaled())
+	if scale > d.scale {
+		value.Mul(value, pow10(int64(scale-d.scale)))
+	}
+	return value
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Add returns the sum of the decimals.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return Decimal{value: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub returns the difference of the decimals.
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return Decimal{value: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Mul returns the product of the decimals, with all their digits.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.unscaled(), other.unscaled()), scale: d.scale + other.scale}
+}
+
+// Div returns the quotient of the decimals with the given number of digits after the decimal point,
+// rounded with the rounding mode. The scale is limited to the range from 0 to 1000, and it panics on a
+// division by zero.
+func (d Decimal) Div(other Decimal, scale int32, mode RoundingMode) Decimal {
+	if other.Sign() == 0 {
+		panic("decimal division by zero")
+	}
+	scale = clampScale(scale)
+
+	numerator := new(big.Int).Mul(d.unscaled(), pow10(int64(other.scale)+int64(scale)))
+	denominator := new(big.Int).Mul(other.unscaled(), pow10(int64(d.scale)))
+	return Decimal{value: roundQuotient(numerator, denominator, mode), scale: scale}
+}
+
+// Round returns the decimal with the given number of digits after the decimal point, rounded with the
+// rounding mode. The scale is limited to the range from 0 to 1000.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	scale = clampScale(scale)
+	if scale >= d.scale {
+		return Decimal{value: d.rescale(scale), scale: scale}
+	}
+	return Decimal{value: roundQuotient(d.unscaled(), pow10(int64(d.scale-scale)), mode), scale: scale}
+}
+
+// clampScale limits a scale to the range from 0 to maxDecimalExponent.
+func clampScale(scale int32) int32 {
+	if scale < 0 {
+		return 0
+	}
+	if scale > maxDecimalExponent {
+		return maxDecimalExponent
+	}
+	return scale
+}
+
+// roundQuotient divides the numerator by the denominator, rounding the quotient with the rounding mode.
+func roundQuotient(numerator *big.Int, denominator *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	sign := numerator.Sign() * denominator.Sign()
+	half := new(big.Int).Abs(remainder)
+	half.Mul(half, big.NewInt(2))
+	cmp := half.Cmp(new(big.Int).Abs(denominator))
+
+	var increment bool
+	switch mode {
+	case RoundUp:
+		increment = true
+	case RoundDown:
+		increment = false
+	case RoundCeiling:
+		increment = sign > 0
+	case RoundFloor:
+		increment = sign < 0
+	case RoundHalfDown:
+		increment = cmp > 0
+	case RoundHalfEven:
+		increment = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+	case RoundHalfUp:
+		increment = cmp >= 0
+	default:
+		panic(fmt.Sprintf("unknown rounding mode %s", mode))
+	}
+
+	if increment {
+		quotient.Add(quotient, big.NewInt(int64(sign)))
+	}
+	return quotient
+}
+
+// maxScale returns the greatest scale of the decimals.
+func maxScale(a Decimal, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Cmp compares the decimals, returning -1 when it's less than the other, 0 when they're equal and +1
+// when it's greater than the other.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := maxScale(d, other)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal checks if the decimals have the same value, regardless of their scales.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign returns -1, 0 or +1 when the decimal is negative, zero or positive.
+func (d Decimal) Sign() int {
+	return d.unscaled().Sign()
+}
+
+// Neg returns the decimal with the opposite sign.
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+// Float64 returns the nearest float of the decimal.
+func (d Decimal) Float64() float64 {
+	value, _ := strconv.ParseFloat(d.String(), 64)
+	return value
+}
+
+// IntPart returns the integer part of the decimal, truncating its fractional part.
+func (d Decimal) IntPart() int64 {
+	return d.Round(0, RoundDown).unscaled().Int64()
+}
+
+// String returns the exact representation of the decimal, with all the digits of its scale.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaled()).String()
+	if d.scale > 0 {
+		if len(digits) <= int(d.scale) {
+			digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON encodes the decimal as a JSON number with all its digits, so it doesn't drift like a
+// float.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes the decimal from a JSON number or string.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if unquoted, err := strconv.Unquote(str); err == nil {
+		str = unquoted
+	}
+
+	parsed, err := ParseDecimal(str)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// toDecimal converts a decimal, a number or a numeric string to a decimal.
+func toDecimal(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case Decimal:
+		return v, true
+	case *Decimal:
+		if v == nil {
+			return nil, false
+		}
+		return *v, true
+	case string:
+		return parseDecimal(v)
+	case json.Number:
+		return parseDecimal(v.String())
+	case float64:
+		return parseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		return parseDecimal(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case bool:
+		return nil, false
+	}
+
+	if v, ok := toInt64(value); ok {
+		return NewDecimalFromInt(v.(int64)), true
+	}
+	return nil, false
+}
+
+// parseDecimal parses a decimal, returning false when it's invalid.
+func parseDecimal(str string) (interface{}, bool) {
+	d, err := ParseDecimal(str)
+	if err != nil {
+		return nil, false
+	}
+	return d, true
+}
+
+// ToDecimal converts a decimal, a number or a numeric string to a decimal.
+func ToDecimal(value interface{}) (Decimal, error) {
+	converted, ok := toDecimal(value)
+	if !ok {
+		return Decimal{}, fmt.Errorf("the value %v can't be converted to decimal", value)
+	}
+	return converted.(Decimal), nil
+}
+
+// ParseRoundingMode parses the name of a rounding mode, like "HALF_EVEN", ignoring its case.
+func ParseRoundingMode(str string) (RoundingMode, error) {
+	mode := RoundingMode(strings.ToUpper(strings.TrimSpace(str)))
+	switch mode {
+	case RoundHalfUp, RoundHalfDown, RoundHalfEven, RoundUp, RoundDown, RoundCeiling, RoundFloor:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown rounding mode %s", str)
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// GetDecimal method get a decimal entry of map. The entry can be a decimal, a number or a numeric
+// string, and the floats are converted from their shortest representation. A missing entry is zero.
+func (c *TypedMap) GetDecimal(param string) Decimal {
+	value := c.Get(param)
+	if value == nil {
+		return Decimal{}
+	}
+
+	converted, ok := toDecimal(value)
+	if !ok {
+		log.Panic("It's not possible to recover this parameter as decimal")
+		panic("It's not possible to recover this parameter as decimal")
+	}
+	return converted.(Decimal)
+}
+
+// GetDecimalOr gets the param as a decimal, or the default value when it's missing or can't be
+// converted.
+func (c *TypedMap) GetDecimalOr(param string, defaultValue Decimal) Decimal {
+	value, ok := c.convert(param, "decimal", toDecimal)
+	if !ok {
+		return defaultValue
+	}
+	return value.(Decimal)
+}
+
+// TryGetDecimal gets the param as a decimal, or zero when it's missing or can't be converted.
+func (c *TypedMap) TryGetDecimal(param string) Decimal {
+	return c.GetDecimalOr(param, Decimal{})
+}
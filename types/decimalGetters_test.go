@@ -0,0 +1,58 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestGetDecimal checks if the decimals are converted from the numbers and the strings without
+// losing precision.
+func TestGetDecimal(t *testing.T) {
+	tm := NewTypedMapFromMap(map[string]interface{}{
+		"string":  "1234.5678901234567890",
+		"float":   0.1,
+		"number":  json.Number("99.99"),
+		"int":     10,
+		"decimal": MustParseDecimal("5.5"),
+		"invalid": "abc",
+	})
+
+	tests := []struct {
+		got      Decimal
+		expected string
+	}{
+		{tm.GetDecimal("string"), "1234.5678901234567890"},
+		{tm.GetDecimal("float"), "0.1"},
+		{tm.GetDecimal("number"), "99.99"},
+		{tm.GetDecimal("int"), "10"},
+		{tm.GetDecimal("decimal"), "5.5"},
+		{tm.GetDecimal("missing"), "0"},
+		{tm.GetDecimalOr("invalid", MustParseDecimal("1.00")), "1.00"},
+		{tm.TryGetDecimal("invalid"), "0"},
+	}
+
+	for i, test := range tests {
+		if test.got.String() != test.expected {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+
+	if got := tm.GetFloat("decimal"); got != 5.5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 5.5, got)
+	}
+	if got := tm.GetString("decimal"); got != "5.5" {
+		t.Errorf("Test Fail, we want %v, we got %v", "5.5", got)
+	}
+}
+
+// TestGetDecimalWithPanic checks if GetDecimal panics when the param isn't a number.
+func TestGetDecimalWithPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on an invalid decimal")
+		}
+	}()
+
+	tm := NewTypedMapFromMap(map[string]interface{}{"invalid": "abc"})
+	tm.GetDecimal("invalid")
+}
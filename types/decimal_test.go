@@ -0,0 +1,153 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseDecimal checks if the decimals are parsed and formatted exactly.
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1234.5678901234567890", "1234.5678901234567890"},
+		{"-0.05", "-0.05"},
+		{".5", "0.5"},
+		{"+7", "7"},
+		{"1.5e3", "1500"},
+		{"125e-4", "0.0125"},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDecimal(test.input)
+		if err != nil || got.String() != test.expected {
+			t.Errorf("Test Fail, we want %v, we got %v (%v)", test.expected, got, err)
+		}
+	}
+
+	if got, err := ParseDecimal("1e1000"); err != nil || len(got.String()) != 1001 {
+		t.Errorf("Test Fail, we want %v, we got %v (%v)", "1 followed by 1000 zeros", got, err)
+	}
+
+	for _, input := range []string{"", "abc", "1.2.3", "1e", "--1", "1e-2147483648", "5e-2147483647", "1e2000000000", "1e1001", "0.5e-1000"} {
+		if _, err := ParseDecimal(input); err == nil {
+			t.Errorf("the decimal %s must be invalid", input)
+		}
+	}
+}
+
+// TestDecimalArithmetic checks if the arithmetic of the decimals is exact.
+func TestDecimalArithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+
+	tests := []struct {
+		got      Decimal
+		expected string
+	}{
+		{a.Add(b), "0.3"},
+		{a.Sub(b), "-0.1"},
+		{MustParseDecimal("19.99").Mul(MustParseDecimal("3")), "59.97"},
+		{MustParseDecimal("10").Div(MustParseDecimal("3"), 4, RoundHalfUp), "3.3333"},
+		{MustParseDecimal("-2").Div(MustParseDecimal("3"), 2, RoundHalfUp), "-0.67"},
+		{MustParseDecimal("1").Div(MustParseDecimal("0.08"), 0, RoundDown), "12"},
+		{Decimal{}.Add(a), "0.1"},
+	}
+
+	for i, test := range tests {
+		if test.got.String() != test.expected {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+
+	if a.Add(b).Cmp(MustParseDecimal("0.30")) != 0 || !a.Equal(MustParseDecimal("0.100")) || a.Cmp(b) != -1 {
+		t.Error("expected the decimals to be compared regardless of their scales")
+	}
+}
+
+// TestDecimalRound checks each rounding mode on the ties and on the negative values.
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		mode     RoundingMode
+		expected []string
+	}{
+		{RoundHalfUp, []string{"2.3", "2.4", "-2.3", "2.3"}},
+		{RoundHalfDown, []string{"2.2", "2.3", "-2.2", "2.3"}},
+		{RoundHalfEven, []string{"2.2", "2.4", "-2.2", "2.3"}},
+		{RoundUp, []string{"2.3", "2.4", "-2.3", "2.3"}},
+		{RoundDown, []string{"2.2", "2.3", "-2.2", "2.2"}},
+		{RoundCeiling, []string{"2.3", "2.4", "-2.2", "2.3"}},
+		{RoundFloor, []string{"2.2", "2.3", "-2.3", "2.2"}},
+	}
+
+	for _, test := range tests {
+		for i, input := range []string{"2.25", "2.35", "-2.25", "2.26"} {
+			got := MustParseDecimal(input).Round(1, test.mode).String()
+			if got != test.expected[i] {
+				t.Errorf("Test Fail on %s of %s, we want %v, we got %v", test.mode, input, test.expected[i], got)
+			}
+		}
+	}
+
+	if got := MustParseDecimal("2.5").Round(3, RoundHalfUp).String(); got != "2.500" {
+		t.Errorf("Test Fail, we want %v, we got %v", "2.500", got)
+	}
+}
+
+// TestDecimalScaleRange checks if the scales of the division and the rounding are limited to the range
+// from 0 to 1000.
+func TestDecimalScaleRange(t *testing.T) {
+	tests := []struct {
+		got      Decimal
+		expected string
+	}{
+		{MustParseDecimal("10").Div(MustParseDecimal("4"), -2, RoundHalfEven), "2"},
+		{MustParseDecimal("10").Div(MustParseDecimal("3"), -1, RoundDown), "3"},
+		{MustParseDecimal("2.567").Round(-5, RoundHalfUp), "3"},
+	}
+
+	for _, test := range tests {
+		if got := test.got; got.Scale() != 0 || !got.Equal(MustParseDecimal(test.expected)) {
+			t.Errorf("Test Fail, we want %v, we got %v with the scale %d", test.expected, got, got.Scale())
+		}
+	}
+
+	if got := MustParseDecimal("1").Div(MustParseDecimal("3"), 5000, RoundDown).Scale(); got != 1000 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1000, got)
+	}
+	if got := MustParseDecimal("1").Round(5000, RoundDown).Scale(); got != 1000 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1000, got)
+	}
+}
+
+// TestDecimalJSON checks if the decimals are encoded as exact JSON numbers and decoded from numbers and
+// strings.
+func TestDecimalJSON(t *testing.T) {
+	result := NewResult()
+	result.Put("limit", MustParseDecimal("12345678901234567.89"))
+
+	data, err := json.Marshal(result.GetFeatures())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"limit":12345678901234567.89}` {
+		t.Errorf("Test Fail, we want %v, we got %v", `{"limit":12345678901234567.89}`, string(data))
+	}
+
+	var decoded []Decimal
+	err = json.Unmarshal([]byte(`[0.1, "2.50"]`), &decoded)
+	if err != nil || decoded[0].String() != "0.1" || decoded[1].String() != "2.50" {
+		t.Errorf("Test Fail, we want %v, we got %v (%v)", "[0.1 2.50]", decoded, err)
+	}
+}
+
+// TestParseRoundingMode checks if the rounding modes are parsed ignoring their case.
+func TestParseRoundingMode(t *testing.T) {
+	if mode, err := ParseRoundingMode("half_even"); err != nil || mode != RoundHalfEven {
+		t.Errorf("Test Fail, we want %v, we got %v", RoundHalfEven, mode)
+	}
+	if _, err := ParseRoundingMode("nearest"); err == nil {
+		t.Error("expected an error on an unknown rounding mode")
+	}
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Assignment is the variant of an experiment assigned to a subject on an evaluation.
+//
+// Property:
+//   - Experiment: the name of the experiment.
+//   - Variant: the assigned variant.
+//   - Subject: the key of the subject, like a customer id.
+type Assignment struct {
+	Experiment string `json:"experiment"`
+	Variant    string `json:"variant"`
+	Subject    string `json:"subject"`
+}
+
+// Experiment method assigns a variant of the experiment to the subject, like a customer id, and returns
+// it. The variants are informed as "name:weight", like `result.Experiment("checkout", ctx.Get("id"),
+// "control:50", "blue:50")`, and the assignment is sticky: the same subject always gets the same
+// variant of the same experiment. The assignment is kept apart from the features of the result, so it
+// never collides with a feature.
+func (r *Result) Experiment(name string, subject interface{}, variants ...string) string {
+	variant, err := SelectVariant(subject, name, variants...)
+	if err != nil {
+		log.Panicf("invalid experiment %s: %v", name, err)
+	}
+
+	assignment := &Assignment{Experiment: name, Variant: variant, Subject: bucketKey(subject)}
+	for i, assigned := range r.assignments {
+		if assigned.Experiment == name {
+			r.assignments[i] = assignment
+			return variant
+		}
+	}
+	r.assignments = append(r.assignments, assignment)
+	return variant
+}
+
+// Assignments method returns the experiments assigned on the evaluation, in the order they were first
+// assigned.
+func (r *Result) Assignments() []*Assignment {
+	return r.assignments
+}
+
+// Experiments method returns the variant of each experiment assigned on the evaluation, by experiment.
+func (r *Result) Experiments() map[string]string {
+	experiments := make(map[string]string, len(r.assignments))
+	for _, assignment := range r.assignments {
+		experiments[assignment.Experiment] = assignment.Variant
+	}
+	return experiments
+}
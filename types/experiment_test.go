@@ -0,0 +1,47 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestResultExperiment checks if the variant is sticky, recorded once by experiment and kept apart
+// from the features.
+func TestResultExperiment(t *testing.T) {
+	result := NewResult()
+
+	variant := result.Experiment("checkout", 12345, "control:50", "blue:50")
+	if again := NewResult().Experiment("checkout", "12345", "control:50", "blue:50"); again != variant {
+		t.Errorf("Test Fail, we want %v, we got %v", variant, again)
+	}
+
+	result.Experiment("checkout", 12345, "control:50", "blue:50")
+	result.Experiment("layout", "ana", "classic:0", "modern:1")
+
+	if got := result.GetFeatures(); len(got) != 0 {
+		t.Errorf("expected no feature, got %v", got)
+	}
+
+	expected := map[string]string{"checkout": variant, "layout": "modern"}
+	if !reflect.DeepEqual(result.Experiments(), expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, result.Experiments())
+	}
+
+	expectedAssignments := []*Assignment{
+		{Experiment: "checkout", Variant: variant, Subject: "12345"},
+		{Experiment: "layout", Variant: "modern", Subject: "ana"},
+	}
+	if !reflect.DeepEqual(result.Assignments(), expectedAssignments) {
+		t.Errorf("Test Fail, we want %v, we got %v", expectedAssignments, result.Assignments())
+	}
+}
+
+// TestResultExperimentWithPanic checks if an experiment without valid variants panics.
+func TestResultExperimentWithPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on an experiment without variants")
+		}
+	}()
+	NewResult().Experiment("checkout", 1)
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FailurePolicy defines what happens to a remote loaded param when its resolver fails.
+type FailurePolicy string
+
+// The failure policies of the remote loaded params.
+const (
+	// FailurePolicyIgnore records the failure under "errors" and leaves the param without value.
+	FailurePolicyIgnore FailurePolicy = ""
+	// FailurePolicyFallback records the failure under "errors" and uses the fallback value of the param.
+	FailurePolicyFallback FailurePolicy = "fallback"
+	// FailurePolicyFail fails the whole evaluation.
+	FailurePolicyFail FailurePolicy = "fail"
+	// FailurePolicyRequired records the param under "requiredParamErrors", like a missing required param.
+	FailurePolicyRequired FailurePolicy = "required"
+)
+
+// RemoteLoadError is the error of an evaluation failed by a remote loaded param with the fail policy.
+//
+// Property:
+//   - Param: the name of the remote loaded param.
+//   - Resolver: the name of the resolver that failed.
+//   - Cause: the message of the failure of the resolver.
+type RemoteLoadError struct {
+	Param    string
+	Resolver string
+	Cause    string
+}
+
+// Error returns the message of the error
+func (e *RemoteLoadError) Error() string {
+	return fmt.Sprintf("the resolver %s failed to load the param %s: %s", e.Resolver, e.Param, e.Cause)
+}
+
+// Failure returns the error that failed the evaluation of the context, if any.
+func (c *Context) Failure() error {
+	if c.failure == nil {
+		return nil
+	}
+	return c.failure
+}
+
+// applyFailurePolicy applies the failure policy of a remote loaded param after the failure of its
+// resolver, returning the value the param must assume. The param is marked as failed, so it isn't
+// loaded again, nor its failure recorded again, on the next accesses.
+func (c *Context) applyFailurePolicy(param string, remote RemoteLoaded, cause interface{}) interface{} {
+	if c.failed == nil {
+		c.failed = make(map[string]bool)
+	}
+	c.failed[param] = true
+	c.addError("errors", param, cause)
+
+	switch remote.Policy {
+	case FailurePolicyFallback:
+		c.Put(param, remote.Fallback)
+		return remote.Fallback
+	case FailurePolicyFail:
+		if c.failure == nil {
+			errs := c.GetMap("errors").GetSlice(param)
+			c.failure = &RemoteLoadError{
+				Param:    param,
+				Resolver: remote.Resolver,
+				Cause:    fmt.Sprintf("%v", errs[len(errs)-1]),
+			}
+		}
+	case FailurePolicyRequired:
+		c.addError("requiredParamErrors", param, fmt.Errorf("parameter %s is required", param))
+	case FailurePolicyIgnore:
+	default:
+		log.Warnf("Unknown failure policy '%s' of the param %s", remote.Policy, param)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"testing"
+)
+
+// setupFailingResolver makes the resolver bridge fail on every call, without retries nor circuit breaker.
+func setupFailingResolver(t *testing.T) {
+	setupResolverResilience(t, 0, 0, 30)
+	Client = &MockHTTPClientExecutePanic{t: t}
+}
+
+// TestFailurePolicyFallback checks if the param assumes the fallback value when the resolver fails.
+func TestFailurePolicyFallback(t *testing.T) {
+	setupFailingResolver(t)
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoadedWithFallback("age", "customer", 18)
+
+	got := ctx.GetInt("age")
+	expected := int64(18)
+
+	if got != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	if len(ctx.GetMap("errors").GetSlice("age")) != 1 {
+		t.Error("the failure must be recorded under errors")
+	}
+
+	if ctx.Failure() != nil {
+		t.Errorf("the evaluation must not fail, got %v", ctx.Failure())
+	}
+}
+
+// TestFailurePolicyFail checks if the failure of the resolver fails the evaluation.
+func TestFailurePolicyFail(t *testing.T) {
+	setupFailingResolver(t)
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoadedWithPolicy("age", "customer", "", string(FailurePolicyFail), nil)
+	ctx.GetEntry("age")
+
+	failure, ok := ctx.Failure().(*RemoteLoadError)
+	if !ok {
+		t.Fatalf("expected a RemoteLoadError, got %v", ctx.Failure())
+	}
+
+	expected := "the resolver customer failed to load the param age: error on execute request"
+	if failure.Error() != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, failure.Error())
+	}
+}
+
+// TestFailurePolicyRequired checks if the param is recorded as a missing required param when the
+// resolver fails.
+func TestFailurePolicyRequired(t *testing.T) {
+	setupFailingResolver(t)
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoadedWithPolicy("age", "customer", "", string(FailurePolicyRequired), nil)
+	ctx.GetEntry("age")
+
+	got := ctx.GetMap("requiredParamErrors").GetSlice("age")[0]
+	expected := "parameter age is required"
+
+	if got != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
+
+// TestFailurePolicyIgnore checks if the param keeps without value when there isn't a failure policy.
+func TestFailurePolicyIgnore(t *testing.T) {
+	setupFailingResolver(t)
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("age", "customer")
+
+	if got := ctx.GetEntry("age"); got != nil {
+		t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+	}
+
+	if ctx.Has("requiredParamErrors") || ctx.Failure() != nil {
+		t.Error("the failure must only be recorded under errors")
+	}
+}
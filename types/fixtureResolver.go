@@ -0,0 +1,147 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// FixtureEntry is an answer of a resolver fixture.
+//
+// Property:
+//   - Match: the context values that select the entry. An entry without values matches any context.
+//   - Values: the values of the params of the resolver.
+//   - Latency: the simulated latency, in milliseconds, overriding the one of the fixture.
+//   - Error: the simulated failure of the resolver.
+type FixtureEntry struct {
+	Match   map[string]interface{} `json:"match"`
+	Values  map[string]interface{} `json:"values"`
+	Latency *int64                 `json:"latency"`
+	Error   string                 `json:"error"`
+}
+
+// FixtureResolver answers the params of a resolver from a fixture file, so the rulesheets can be
+// evaluated without the resolver bridge.
+//
+// Property:
+//   - Latency: the simulated latency, in milliseconds, of each answer.
+//   - ErrorRate: the fraction, from 0 to 1, of the answers that fail with a simulated error.
+//   - Entries: the answers of the resolver. The first one matching the context is used.
+type FixtureResolver struct {
+	Latency   int64          `json:"latency"`
+	ErrorRate float64        `json:"errorRate"`
+	Entries   []FixtureEntry `json:"entries"`
+}
+
+// resolve can't match the entries without the entries of the context, so it only matches the entries
+// without values to match.
+func (f *FixtureResolver) resolve(resolver string, param string) interface{} {
+	return f.resolveWith(nil, resolver, param)
+}
+
+// resolveWith answers the param from the first entry matching the entries of the context, after the
+// simulated latency. It panics, like a resolver bridge failure, with the simulated errors and when no
+// entry matches.
+func (f *FixtureResolver) resolveWith(entries map[string]interface{}, resolver string, param string) interface{} {
+	entry := f.match(entries)
+	if entry == nil {
+		log.Panicf("there isn't a fixture of the resolver %s matching the context", resolver)
+	}
+
+	latency := f.Latency
+	if entry.Latency != nil {
+		latency = *entry.Latency
+	}
+	if latency > 0 {
+		time.Sleep(time.Duration(latency) * time.Millisecond)
+	}
+
+	if entry.Error != "" {
+		log.Panic(entry.Error)
+	}
+
+	if f.ErrorRate > 0 && rand.Float64() < f.ErrorRate {
+		log.Panicf("simulated error of the resolver %s", resolver)
+	}
+
+	return entry.Values[param]
+}
+
+// match returns the first entry whose values are equal to the ones of the context.
+func (f *FixtureResolver) match(entries map[string]interface{}) *FixtureEntry {
+	for i := range f.Entries {
+		entry := &f.Entries[i]
+		matched := true
+		for key, expected := range entry.Match {
+			value, ok := entries[key]
+			if !ok || fmt.Sprintf("%v", value) != fmt.Sprintf("%v", expected) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return entry
+		}
+	}
+	return nil
+}
+
+// LoadFixtureResolver loads a FixtureResolver from a JSON file.
+func LoadFixtureResolver(path string) (*FixtureResolver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &FixtureResolver{}
+	err = json.Unmarshal(data, fixture)
+	if err != nil {
+		return nil, fmt.Errorf("error on decode the fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+var fixtureResolversMutex sync.Mutex
+
+// fixtureResolvers are the loaded fixtures by directory and resolver name.
+var fixtureResolvers = make(map[string]*FixtureResolver)
+
+// getFixtureResolver returns the fixture of a resolver, loaded from the file named after the resolver
+// on `FEATWS_RULLER_RESOLVER_FIXTURES`, or false when the fixture mode is disabled.
+func getFixtureResolver(resolver string) (Resolver, bool) {
+	dir := config.GetConfig().ResolverFixturesPath
+	if dir == "" {
+		return nil, false
+	}
+
+	fixtureResolversMutex.Lock()
+	defer fixtureResolversMutex.Unlock()
+
+	path := filepath.Join(dir, resolver+".json")
+	if fixture, ok := fixtureResolvers[path]; ok {
+		return fixture, true
+	}
+
+	fixture, err := LoadFixtureResolver(path)
+	if err != nil {
+		log.WithError(err).Panicf("error on load the fixture of the resolver %s", resolver)
+	}
+	fixtureResolvers[path] = fixture
+	return fixture, true
+}
+
+// getInProcessResolver returns the resolver that answers in-process instead of the resolver bridge:
+// the plugin registered with the name or, on the fixture mode, the fixture of the resolver.
+func getInProcessResolver(resolver string) (Resolver, bool) {
+	if plugin, ok := getResolverPlugin(resolver); ok {
+		return plugin, true
+	}
+	return getFixtureResolver(resolver)
+}
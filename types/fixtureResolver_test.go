@@ -0,0 +1,124 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// setupResolverFixtures writes the fixtures on a temporary directory and enables the fixture mode.
+func setupResolverFixtures(t *testing.T, fixtures map[string]string) {
+	dir := t.TempDir()
+	for resolver, content := range fixtures {
+		err := os.WriteFile(filepath.Join(dir, resolver+".json"), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config.GetConfig().ResolverFixturesPath = dir
+	t.Cleanup(func() {
+		config.LoadConfig()
+	})
+}
+
+// TestFixtureResolverMatch checks if the params are answered from the fixture entry matching the
+// context, without calling the resolver bridge.
+func TestFixtureResolverMatch(t *testing.T) {
+	setupResolverFixtures(t, map[string]string{
+		"customer": `{"entries": [
+			{"match": {"id": 1}, "values": {"age": 30, "name": "Ana"}},
+			{"match": {"id": "2"}, "values": {"age": 17, "name": "Bia"}},
+			{"values": {"age": 0, "name": "unknown"}}
+		]}`,
+	})
+
+	client := &MockHTTPClientBatch{}
+	Client = client
+
+	tests := []struct {
+		id   interface{}
+		age  int64
+		name string
+	}{
+		{"1", 30, "Ana"},
+		{2, 17, "Bia"},
+		{3, 0, "unknown"},
+	}
+
+	for _, test := range tests {
+		ctx := NewContextFromMap(map[string]interface{}{"id": test.id})
+		ctx.RegistryRemoteLoaded("age", "customer")
+		ctx.RegistryRemoteLoaded("name", "customer")
+
+		if got := ctx.GetInt("age"); got != test.age {
+			t.Errorf("Test Fail, we want %v, we got %v", test.age, got)
+		}
+		if got := ctx.GetString("name"); got != test.name {
+			t.Errorf("Test Fail, we want %v, we got %v", test.name, got)
+		}
+	}
+
+	if len(client.loads) != 0 {
+		t.Errorf("the resolver bridge must not be called, got %v", client.loads)
+	}
+}
+
+// TestFixtureResolverError checks if the simulated errors and the contexts without a matching entry
+// fail like the resolver bridge.
+func TestFixtureResolverError(t *testing.T) {
+	setupResolverFixtures(t, map[string]string{
+		"customer": `{"entries": [
+			{"match": {"id": 1}, "error": "customer unavailable"}
+		]}`,
+		"broken": `{"errorRate": 1, "entries": [{"values": {"age": 1}}]}`,
+	})
+
+	tests := []struct {
+		resolver string
+		id       int
+		expected string
+	}{
+		{"customer", 1, "customer unavailable"},
+		{"customer", 2, "there isn't a fixture of the resolver customer matching the context"},
+		{"broken", 1, "simulated error of the resolver broken"},
+		{"missing", 1, "error on load the fixture of the resolver missing"},
+	}
+
+	for _, test := range tests {
+		ctx := NewContextFromMap(map[string]interface{}{"id": test.id})
+		ctx.RegistryRemoteLoadedWithPolicy("age", test.resolver, "", "fail", nil)
+		ctx.load("age")
+
+		if ctx.Failure() == nil {
+			t.Errorf("expected a failure of the resolver %s", test.resolver)
+			continue
+		}
+		if cause := ctx.Failure().(*RemoteLoadError).Cause; cause != test.expected {
+			t.Errorf("Test Fail, we want %v, we got %v", test.expected, cause)
+		}
+	}
+}
+
+// TestFixtureResolverLatency checks if the latency of the fixture entry is simulated.
+func TestFixtureResolverLatency(t *testing.T) {
+	setupResolverFixtures(t, map[string]string{
+		"customer": `{"latency": 1000, "entries": [{"values": {"age": 30}, "latency": 20}]}`,
+	})
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("age", "customer")
+
+	start := time.Now()
+	if got := ctx.GetInt("age"); got != 30 {
+		t.Errorf("Test Fail, we want %v, we got %v", 30, got)
+	}
+
+	elapsed := time.Since(start)
+	if elapsed < 20*time.Millisecond || elapsed >= time.Second {
+		t.Errorf("expected the latency of the entry, got %v", elapsed)
+	}
+}
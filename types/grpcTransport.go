@@ -0,0 +1,148 @@
+package types
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/resolvergrpc"
+	telemetry "github.com/bancodobrasil/gin-telemetry"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCDialOptions are the options used to connect to the gRPC resolver bridges, replacing the default
+// credentials, like the dialer of an in-process fake server on the tests.
+var GRPCDialOptions []grpc.DialOption
+
+var grpcConnsMutex sync.Mutex
+
+// grpcConns are the connections to the gRPC resolver bridges by address, shared by all the requests.
+var grpcConns = make(map[string]*grpc.ClientConn)
+
+// getGRPCConn returns the connection to a gRPC resolver bridge, creating it on the first use.
+func getGRPCConn(bridge *config.ResolverBridge) (*grpc.ClientConn, error) {
+	grpcConnsMutex.Lock()
+	defer grpcConnsMutex.Unlock()
+
+	address, secure := bridge.GRPCTarget()
+	if conn, ok := grpcConns[address]; ok {
+		return conn, nil
+	}
+
+	opts := GRPCDialOptions
+	if len(opts) == 0 {
+		creds := insecure.NewCredentials()
+		if secure {
+			creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: config.GetConfig().DisableSSLVerify})
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	}
+
+	conn, err := grpc.Dial(address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	grpcConns[address] = conn
+	return conn, nil
+}
+
+// ResetGRPCConns closes the connections to the gRPC resolver bridges, so they're created again on the
+// next use.
+func ResetGRPCConns() {
+	grpcConnsMutex.Lock()
+	defer grpcConnsMutex.Unlock()
+
+	for address, conn := range grpcConns {
+		conn.Close()
+		delete(grpcConns, address)
+	}
+}
+
+// resolveAllGRPC loads the params of a resolver in a single call to a gRPC resolver bridge, with the
+// same timeout, retries and circuit breaker of the HTTP transport. The deadline of the `RawContext`,
+// the headers of the bridge and the telemetry are propagated on the call, and a call canceled by it isn't
+// counted as a failure of the resolver.
+func (c *Context) resolveAllGRPC(resolver string, bridge *config.ResolverBridge, input resolveInputV1, call *breakerCall) resolveOutputV1 {
+	conf := config.GetConfig()
+
+	conn, err := getGRPCConn(bridge)
+	if err != nil {
+		log.WithError(err).Panic("error on create Request")
+	}
+
+	ctx := c.RawContext
+	parent := ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	header := bridge.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if !telemetry.MiddlewareDisabled && ctx != nil {
+		telemetry.Inject(ctx, header)
+	}
+	md := metadata.MD{}
+	for key, values := range header {
+		md[strings.ToLower(key)] = values
+	}
+	parent = metadata.NewOutgoingContext(parent, md)
+
+	req := &resolvergrpc.ResolveRequest{
+		Resolver: resolver,
+		Context:  input.Context,
+		Load:     input.Load,
+	}
+
+	backoff := time.Duration(conf.ResolverRetryBackoff) * time.Millisecond
+
+	for attempt := int64(0); ; attempt++ {
+		reqCtx, cancel := parent, context.CancelFunc(func() {})
+		if bridge.Timeout > 0 {
+			reqCtx, cancel = context.WithTimeout(parent, bridge.Timeout)
+		}
+
+		resp, err := resolvergrpc.Resolve(reqCtx, conn, req)
+		cancel()
+
+		code := status.Code(err)
+		retryable := (code == codes.Unavailable || code == codes.DeadlineExceeded) && parent.Err() == nil
+		if retryable && attempt < conf.ResolverRetries {
+			log.WithField("resolver", resolver).Debugf("retrying the resolver request, attempt %d", attempt+1)
+			resolverRetries.WithLabelValues(resolver).Inc()
+
+			select {
+			case <-parent.Done():
+			case <-time.After(backoff << attempt):
+			}
+			continue
+		}
+
+		if err != nil {
+			if parent.Err() != nil {
+				call.canceled()
+				log.WithError(err).Panic("the request was canceled")
+			}
+			call.failure()
+			log.WithError(err).Panic("error on execute request")
+		}
+		call.success()
+
+		return resolveOutputV1{
+			Context: resp.Context,
+			Errors:  resp.Errors,
+			Error:   resp.Error,
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package types
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/bancodobrasil/featws-ruller/resolvergrpc"
+	telemetry "github.com/bancodobrasil/gin-telemetry"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// setupGRPCResolverBridge routes the default resolver bridge to an in-process gRPC fake server for a test.
+func setupGRPCResolverBridge(t *testing.T, resolve resolvergrpc.ResolverFunc) {
+	fake := resolvergrpc.NewFakeServer(resolve)
+	GRPCDialOptions = fake.DialOptions()
+
+	cfg := config.GetConfig()
+	cfg.ResolverBridgeURL = "grpc://bridge:9090"
+	cfg.ResolverBridgeTransport = config.ResolverTransportGRPC
+	cfg.ResolverBridgeHeaders.Set("X-Api-Key", "secret")
+	cfg.ResolverRetryBackoff = 1
+
+	t.Cleanup(func() {
+		ResetGRPCConns()
+		GRPCDialOptions = nil
+		fake.Close()
+		config.LoadConfig()
+	})
+}
+
+// TestGRPCTransport checks if the params of a resolver are loaded in a single gRPC call, with the
+// headers of the bridge and the deadline of the request.
+func TestGRPCTransport(t *testing.T) {
+	var requests []*resolvergrpc.ResolveRequest
+	var apiKey []string
+	var hasDeadline bool
+
+	setupGRPCResolverBridge(t, func(ctx context.Context, req *resolvergrpc.ResolveRequest) (*resolvergrpc.ResolveResponse, error) {
+		requests = append(requests, req)
+		md, _ := metadata.FromIncomingContext(ctx)
+		apiKey = md.Get("x-api-key")
+		_, hasDeadline = ctx.Deadline()
+
+		values := make(map[string]interface{})
+		for _, param := range req.Load {
+			values[param] = "value of " + param
+		}
+		return &resolvergrpc.ResolveResponse{Context: values}, nil
+	})
+
+	telemetry.MiddlewareDisabled = true
+	defer func() {
+		telemetry.MiddlewareDisabled = false
+	}()
+
+	rawCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx := NewContextFromMap(map[string]interface{}{"branch": "0001"})
+	ctx.RawContext = rawCtx
+	ctx.RegistryRemoteLoaded("age", "customer")
+	ctx.RegistryRemoteLoadedWithFrom("name", "customer", "fullname")
+
+	if got := ctx.GetString("name"); got != "value of fullname" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of fullname", got)
+	}
+
+	if got := ctx.GetString("age"); got != "value of age" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of age", got)
+	}
+
+	if len(requests) != 1 || requests[0].Resolver != "customer" || requests[0].Context["branch"] != "0001" {
+		t.Fatalf("expected a single call to the customer resolver, got %v", requests)
+	}
+
+	if len(apiKey) != 1 || apiKey[0] != "secret" {
+		t.Errorf("Test Fail, we want %v, we got %v", "secret", apiKey)
+	}
+
+	if !hasDeadline {
+		t.Error("expected the timeout of the bridge as the deadline of the call")
+	}
+}
+
+// TestGRPCTransportErrors checks if the errors of the response and the failed calls are recorded, and
+// if the unavailable bridge is retried.
+func TestGRPCTransportErrors(t *testing.T) {
+	calls := 0
+	setupGRPCResolverBridge(t, func(ctx context.Context, req *resolvergrpc.ResolveRequest) (*resolvergrpc.ResolveResponse, error) {
+		calls++
+		if calls == 1 {
+			return nil, status.Error(codes.Unavailable, "try again")
+		}
+		if req.Resolver == "broken" {
+			return nil, status.Error(codes.Internal, "broken resolver")
+		}
+		return &resolvergrpc.ResolveResponse{Errors: map[string]interface{}{"myparam": "myerror"}}, nil
+	})
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "myresolver")
+	ctx.RegistryRemoteLoaded("other", "broken")
+	ctx.load("myparam")
+	ctx.load("other")
+
+	if calls != 3 {
+		t.Errorf("expected the unavailable call to be retried, got %d calls", calls)
+	}
+
+	if got := ctx.GetMap("errors").GetSlice("myparam")[0]; got != "map[myparam:myerror]" {
+		t.Errorf("Test Fail, we want %v, we got %v", "map[myparam:myerror]", got)
+	}
+
+	if got := ctx.GetMap("errors").GetSlice("other")[0]; got != "error on execute request" {
+		t.Errorf("Test Fail, we want %v, we got %v", "error on execute request", got)
+	}
+}
+
+// TestGRPCTransportCanceled checks if the calls canceled by the `RawContext`, or past its deadline, are
+// recorded without being retried or counted as failures of the circuit breaker.
+func TestGRPCTransportCanceled(t *testing.T) {
+	calls := 0
+	setupGRPCResolverBridge(t, func(ctx context.Context, req *resolvergrpc.ResolveRequest) (*resolvergrpc.ResolveResponse, error) {
+		calls++
+		return &resolvergrpc.ResolveResponse{Context: map[string]interface{}{"myparam": "myvalue"}}, nil
+	})
+
+	cfg := config.GetConfig()
+	cfg.ResolverRetries = 2
+	cfg.ResolverBreakerThreshold = 1
+	cfg.ResolverBreakerCooldown = 30
+
+	telemetry.MiddlewareDisabled = true
+	defer func() {
+		telemetry.MiddlewareDisabled = false
+	}()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	for _, rawCtx := range []context.Context{canceled, expired} {
+		ctx := NewContext()
+		ctx.RawContext = rawCtx
+		ctx.RegistryRemoteLoaded("myparam", "grpccanceledresolver")
+
+		if got := ctx.GetEntry("myparam"); got != nil {
+			t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+		}
+		if got := ctx.GetMap("errors").GetSlice("myparam"); len(got) != 1 || got[0] != "the request was canceled" {
+			t.Errorf("Test Fail, we want %v, we got %v", "the request was canceled", got)
+		}
+	}
+
+	if got := testutil.ToFloat64(circuitBreakerState.WithLabelValues("grpccanceledresolver")); got != CircuitBreakerClosed {
+		t.Errorf("Test Fail, we want %v, we got %v", CircuitBreakerClosed, got)
+	}
+
+	if got := testutil.ToFloat64(resolverRetries.WithLabelValues("grpccanceledresolver")); got != 0 {
+		t.Errorf("expected no retry of the canceled calls, got %v", got)
+	}
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("myparam", "grpccanceledresolver")
+	if got := ctx.GetString("myparam"); got != "myvalue" {
+		t.Errorf("Test Fail, we want %v, we got %v", "myvalue", got)
+	}
+
+	if calls != 1 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1, calls)
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import "testing"
+
+// TestMurmur3 checks the hash against the reference vectors of MurmurHash3 x86_32.
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		data     string
+		seed     uint32
+		expected uint32
+	}{
+		{"", 0, 0},
+		{"", 1, 0x514e28b7},
+		{"", 0xffffffff, 0x81f16f39},
+		{"\x00\x00\x00\x00", 0, 0x2362f9de},
+		{"\x21\x43\x65\x87", 0x5082edee, 0x2362f9de},
+		{"\x21\x43\x65", 0, 0x7e4a8634},
+		{"\x21\x43", 0, 0xa0f7b07a},
+		{"\x21", 0, 0x72661cf4},
+		{"hello", 0, 0x248bfa47},
+		{"Hello, world!", 0, 0xc0363e43},
+		{"The quick brown fox jumps over the lazy dog", 0, 0x2e4ff723},
+	}
+
+	for _, test := range tests {
+		if got := murmur3([]byte(test.data), test.seed); got != test.expected {
+			t.Errorf("Test Fail on %q, we want %#x, we got %#x", test.data, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// prefetchResult is the outcome of prefetching the params of a resolver.
+//
+// Property:
+//   - params: the remote loaded params prefetched, by param name.
+//   - values: the resolved values by the param requested to the resolver (the `From` of the remote loaded param).
+//   - err: the recovered panic of the resolver, if it failed.
+type prefetchResult struct {
+	params map[string]RemoteLoaded
+	values map[string]interface{}
+	err    interface{}
+}
+
+// Prefetch loads all the pending remote loaded params, calling the distinct resolvers concurrently, so
+// the latency is the one of the slowest resolver instead of the sum of them. The number of concurrent
+// calls is bounded by `FEATWS_RULLER_RESOLVER_PREFETCH_CONCURRENCY` and no call is started after the
+// `RawContext` is canceled. A resolver that fails is just skipped: its params stay pending and are
+// loaded, recording the error, on their first access. The params that the resolver failed to resolve,
+// while resolving the others, get their failure policies applied right away.
+func (c *Context) Prefetch() {
+	groups := make(map[string]map[string]RemoteLoaded)
+	for param, remote := range c.RemoteLoadeds {
+		if c.TypedMap.GetEntry(param) != nil || c.failed[param] {
+			continue
+		}
+		if _, ok := groups[remote.Resolver]; !ok {
+			groups[remote.Resolver] = c.pendingRemoteLoadeds(remote.Resolver)
+		}
+	}
+
+	if len(groups) == 0 {
+		return
+	}
+
+	entries := make(map[string]interface{})
+	if c.Resolver == nil {
+		for key, value := range c.GetEntries() {
+			entries[key] = value
+		}
+	}
+
+	rawCtx := c.RawContext
+	if rawCtx == nil {
+		rawCtx = context.Background()
+	}
+
+	concurrency := int(config.GetConfig().ResolverPrefetchConcurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+
+	results := make(map[string]*prefetchResult)
+	var wg sync.WaitGroup
+
+	for resolver, params := range groups {
+		select {
+		case <-rawCtx.Done():
+		case slots <- struct{}{}:
+		}
+		if rawCtx.Err() != nil {
+			break
+		}
+
+		result := &prefetchResult{params: params}
+		results[resolver] = result
+
+		wg.Add(1)
+		go func(resolver string, result *prefetchResult) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer func() {
+				result.err = recover()
+			}()
+			result.values = c.resolveAllFrom(entries, resolver, remoteLoadedFroms(result.params))
+		}(resolver, result)
+	}
+
+	wg.Wait()
+
+	for resolver, result := range results {
+		if result.err != nil {
+			log.WithField("resolver", resolver).Debugf("error on prefetch: %v", result.err)
+			continue
+		}
+		for param, remote := range result.params {
+			if failure, failed := result.values[remote.From].(*paramError); failed {
+				c.applyFailurePolicy(param, remote, failure.message)
+				continue
+			}
+			c.Put(param, result.values[remote.From])
+		}
+	}
+}
+
+// remoteLoadedFroms returns the distinct params requested to the resolver for the remote loaded params,
+// sorted.
+func remoteLoadedFroms(params map[string]RemoteLoaded) []string {
+	froms := make([]string, 0, len(params))
+	fromSet := make(map[string]bool)
+	for _, remote := range params {
+		if !fromSet[remote.From] {
+			fromSet[remote.From] = true
+			froms = append(froms, remote.From)
+		}
+	}
+	sort.Strings(froms)
+	return froms
+}
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// MockHTTPClientPrefetch is a mock of the resolver bridge that takes some time to answer and records
+// the maximum number of concurrent calls.
+//
+// Property:
+//   - mutex: protects the counters of the concurrent calls.
+//   - inFlight: the number of calls being answered.
+//   - maxInFlight: the maximum number of concurrent calls.
+//   - calls: the number of calls.
+type MockHTTPClientPrefetch struct {
+	http.Client
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       int
+}
+
+// Do answers each loaded param with its name prefixed by the resolver name after a short delay.
+func (m *MockHTTPClientPrefetch) Do(req *http.Request) (*http.Response, error) {
+	m.mutex.Lock()
+	m.calls++
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	m.mutex.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	m.mutex.Lock()
+	m.inFlight--
+	m.mutex.Unlock()
+
+	input := resolveInputV1{}
+	err := json.NewDecoder(req.Body).Decode(&input)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	resolver := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+
+	output := resolveOutputV1{Context: map[string]interface{}{}}
+	for _, param := range input.Load {
+		output.Context[param] = resolver + " " + param
+	}
+
+	data, _ := json.Marshal(output)
+
+	return &http.Response{
+		Body: io.NopCloser(strings.NewReader(string(data))),
+	}, nil
+}
+
+// TestPrefetch checks if the distinct resolvers are called concurrently, bounded by the configured limit.
+func TestPrefetch(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.ResolverPrefetchConcurrency = 2
+	defer config.LoadConfig()
+
+	client := &MockHTTPClientPrefetch{}
+	Client = client
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("a1", "a")
+	ctx.RegistryRemoteLoaded("a2", "a")
+	ctx.RegistryRemoteLoaded("b1", "b")
+	ctx.RegistryRemoteLoaded("c1", "c")
+
+	ctx.Prefetch()
+
+	if client.calls != 3 {
+		t.Errorf("expected a call by resolver, got %d", client.calls)
+	}
+
+	if client.maxInFlight != 2 {
+		t.Errorf("expected 2 concurrent calls, got %d", client.maxInFlight)
+	}
+
+	for param, expected := range map[string]string{"a1": "a a1", "a2": "a a2", "b1": "b b1", "c1": "c c1"} {
+		if got := ctx.GetString(param); got != expected {
+			t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+		}
+	}
+
+	if client.calls != 3 {
+		t.Errorf("the prefetched params must not be loaded again, got %d calls", client.calls)
+	}
+}
+
+// TestPrefetchCanceled checks if no resolver is called after the raw context is canceled.
+func TestPrefetchCanceled(t *testing.T) {
+	client := &MockHTTPClientPrefetch{}
+	Client = client
+
+	rawCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx := NewContext()
+	ctx.RawContext = rawCtx
+	ctx.RegistryRemoteLoaded("a1", "a")
+	ctx.RegistryRemoteLoaded("b1", "b")
+
+	ctx.Prefetch()
+
+	if client.calls != 0 {
+		t.Errorf("expected no calls, got %d", client.calls)
+	}
+}
+
+// TestSetRequiredConfiguredPrefetch checks if the params are prefetched when the required params are
+// configured and the prefetch is enabled.
+func TestSetRequiredConfiguredPrefetch(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.ResolverPrefetch = true
+	defer config.LoadConfig()
+
+	client := &MockHTTPClientPrefetch{}
+	Client = client
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("a1", "a")
+	ctx.SetRequiredConfigured()
+
+	if !ctx.TypedMap.Has("a1") || client.calls != 1 {
+		t.Errorf("expected the param to be prefetched, got %d calls", client.calls)
+	}
+}
+
+// TestPrefetchParamErrors checks if the params that the resolver failed to resolve while resolving the
+// others get their failure policies on the prefetch and aren't loaded again on their first access.
+func TestPrefetchParamErrors(t *testing.T) {
+	client := setupResolverProtocolV2(t)
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("age", "customer")
+	ctx.RegistryRemoteLoadedWithPolicy("score", "customer", "", string(FailurePolicyRequired), nil)
+	ctx.Prefetch()
+
+	if got := ctx.GetMap("requiredParamErrors").GetSlice("score"); len(got) != 1 {
+		t.Errorf("expected the required policy of the score on the prefetch, got %v", got)
+	}
+
+	if got := ctx.GetEntry("score"); got != nil {
+		t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+	}
+	ctx.GetInt("score")
+
+	if got := ctx.GetMap("errors").GetSlice("score"); len(got) != 1 || got[0] != "score unavailable" {
+		t.Errorf("Test Fail, we want %v, we got %v", []interface{}{"score unavailable"}, got)
+	}
+
+	if len(client.requests) != 1 {
+		t.Errorf("expected a single call to the resolver bridge, got %d calls", len(client.requests))
+	}
+}
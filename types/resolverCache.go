@@ -0,0 +1,233 @@
+package types
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// resolverCacheRequests counts the lookups on the resolver cache by resolver and result, that can be
+// "hit" or "miss".
+var resolverCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "featws_ruller_resolver_cache_requests_total",
+	Help: "Lookups on the cache of the resolver responses by result.",
+}, []string{"resolver", "result"})
+
+// resolverCacheEntry is a cached value of a param.
+type resolverCacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// resolverCache is a LRU cache of the values resolved by a resolver, shared by all the requests. It's
+// safe to be used concurrently.
+//
+// Property:
+//   - mutex: protects the entries of the cache.
+//   - settings: the TTL, size and keys of the cache.
+//   - entries: the elements of the LRU list by key.
+//   - lru: the cached entries, from the most to the least recently used.
+type resolverCache struct {
+	mutex    sync.Mutex
+	settings *config.ResolverCache
+	entries  map[string]*list.Element
+	lru      *list.List
+}
+
+// newResolverCache creates an empty cache with the given settings
+func newResolverCache(settings *config.ResolverCache) *resolverCache {
+	return &resolverCache{
+		settings: settings,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// key builds the cache key of a param from the configured context keys of the entries sent to the
+// resolver, or from all the entries if there are no keys configured.
+func (rc *resolverCache) key(param string, entries map[string]interface{}) (string, bool) {
+	identity := entries
+	if len(rc.settings.Keys) > 0 {
+		identity = make(map[string]interface{})
+		for _, key := range rc.settings.Keys {
+			identity[key] = entries[key]
+		}
+	}
+
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return "", false
+	}
+	return param + "\x00" + string(data), true
+}
+
+// get returns a copy of the cached value of the key, if it exists and isn't expired, so the requests
+// don't share the maps and the slices of the value.
+func (rc *resolverCache) get(key string) (interface{}, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	element, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*resolverCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		rc.lru.Remove(element)
+		delete(rc.entries, key)
+		return nil, false
+	}
+
+	rc.lru.MoveToFront(element)
+	return copyValue(entry.value), true
+}
+
+// put stores the value of the key, evicting the least recently used entry when the cache is full.
+func (rc *resolverCache) put(key string, value interface{}) {
+	rc.putWithTTL(key, value, rc.settings.TTL)
+}
+
+// putWithTTL works like put, but the entry expires after the given TTL when it's lower than the TTL of
+// the cache, like when the resolver bridge sends a max-age hint. A non positive TTL isn't cached. A copy
+// of the value is stored, so the request that resolved it can still change it.
+func (rc *resolverCache) putWithTTL(key string, value interface{}, ttl time.Duration) {
+	if ttl > rc.settings.TTL {
+		ttl = rc.settings.TTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	value = copyValue(value)
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+
+	if element, ok := rc.entries[key]; ok {
+		entry := element.Value.(*resolverCacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		rc.lru.MoveToFront(element)
+		return
+	}
+
+	for rc.lru.Len() >= rc.settings.Size && rc.lru.Len() > 0 {
+		oldest := rc.lru.Back()
+		rc.lru.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*resolverCacheEntry).key)
+	}
+
+	rc.entries[key] = rc.lru.PushFront(&resolverCacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+}
+
+// copyValue returns a deep copy of the maps and the slices of a value decoded from JSON. The other
+// values, like strings, numbers, times and decimals, are immutable and returned as they are.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	case *TypedMap:
+		return NewTypedMapFromMap(copyValue(map[string]interface{}(v.interfaceMap)).(map[string]interface{}))
+	}
+	return value
+}
+
+var resolverCachesMutex sync.Mutex
+
+// resolverCaches are the caches of the resolvers configured on `FEATWS_RULLER_RESOLVER_CACHE_TTL`, by
+// resolver name. They're created on the first use.
+var resolverCaches map[string]*resolverCache
+
+// getResolverCache returns the cache of a resolver, or nil if the resolver isn't cached.
+func getResolverCache(resolver string) *resolverCache {
+	resolverCachesMutex.Lock()
+	defer resolverCachesMutex.Unlock()
+
+	if resolverCaches == nil {
+		resolverCaches = make(map[string]*resolverCache)
+		for name, settings := range config.GetConfig().ResolverCache {
+			resolverCaches[name] = newResolverCache(settings)
+		}
+	}
+	return resolverCaches[resolver]
+}
+
+// resetResolverCaches drops all the caches, so they're created again from the configuration on the
+// next use.
+func resetResolverCaches() {
+	resolverCachesMutex.Lock()
+	defer resolverCachesMutex.Unlock()
+	resolverCaches = nil
+}
+
+// resolveCachedImpl resolves the params with the resolver bridge, answering from the cache of the
+// resolver the params already cached and caching the resolved ones. The cache is skipped when the
+// context asks to bypass it. The max-age hints of the resolver bridge shorten the TTL of the cached
+// values, and the params that failed aren't cached.
+func (c *Context) resolveCachedImpl(resolver string, entries map[string]interface{}, params []string) map[string]interface{} {
+	cache := getResolverCache(resolver)
+	if cache == nil || c.BypassResolverCache {
+		values, _ := c.resolveAllImpl(resolver, entries, params)
+		return values
+	}
+
+	values := make(map[string]interface{})
+	keys := make(map[string]string)
+	missing := []string{}
+
+	for _, param := range params {
+		key, ok := cache.key(param, entries)
+		if ok {
+			if value, hit := cache.get(key); hit {
+				resolverCacheRequests.WithLabelValues(resolver, "hit").Inc()
+				values[param] = value
+				continue
+			}
+			keys[param] = key
+		}
+		resolverCacheRequests.WithLabelValues(resolver, "miss").Inc()
+		missing = append(missing, param)
+	}
+
+	if len(missing) == 0 {
+		return values
+	}
+
+	resolved, maxAges := c.resolveAllImpl(resolver, entries, missing)
+	for param, value := range resolved {
+		values[param] = value
+		if _, failed := value.(*paramError); failed {
+			continue
+		}
+		if key, ok := keys[param]; ok && value != nil {
+			ttl := cache.settings.TTL
+			if maxAge, ok := maxAges[param]; ok {
+				ttl = maxAge
+			}
+			cache.putWithTTL(key, value, ttl)
+		}
+	}
+	return values
+}
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// setupResolverCache configures the cache of a resolver for a test and drops it at the end.
+func setupResolverCache(t *testing.T, resolver string, settings *config.ResolverCache) {
+	cfg := config.GetConfig()
+	cfg.ResolverCache = map[string]*config.ResolverCache{resolver: settings}
+	resetResolverCaches()
+	t.Cleanup(func() {
+		config.LoadConfig()
+		resetResolverCaches()
+	})
+}
+
+// TestResolverCache checks if the responses are cached by the configured context keys across contexts.
+func TestResolverCache(t *testing.T) {
+	setupResolverCache(t, "cachedresolver", &config.ResolverCache{TTL: time.Minute, Size: 10, Keys: []string{"id"}})
+
+	client := &MockHTTPClientBatch{}
+	Client = client
+
+	for _, values := range []map[string]interface{}{
+		{"id": "1", "other": "a"},
+		{"id": "1", "other": "b"},
+		{"id": "2", "other": "a"},
+	} {
+		ctx := NewContextFromMap(values)
+		ctx.RegistryRemoteLoaded("myparam", "cachedresolver")
+		if got := ctx.GetString("myparam"); got != "value of myparam" {
+			t.Errorf("Test Fail, we want %v, we got %v", "value of myparam", got)
+		}
+	}
+
+	if len(client.loads) != 2 {
+		t.Errorf("expected a call by id, got %v", client.loads)
+	}
+
+	if got := testutil.ToFloat64(resolverCacheRequests.WithLabelValues("cachedresolver", "hit")); got != 1 {
+		t.Errorf("expected 1 hit, got %v", got)
+	}
+}
+
+// TestResolverCacheBypass checks if the context can bypass the cache.
+func TestResolverCacheBypass(t *testing.T) {
+	setupResolverCache(t, "bypassresolver", &config.ResolverCache{TTL: time.Minute, Size: 10, Keys: []string{"id"}})
+
+	client := &MockHTTPClientBatch{}
+	Client = client
+
+	for i := 0; i < 2; i++ {
+		ctx := NewContextFromMap(map[string]interface{}{"id": "1"})
+		ctx.BypassResolverCache = true
+		ctx.RegistryRemoteLoaded("myparam", "bypassresolver")
+		ctx.GetString("myparam")
+	}
+
+	if len(client.loads) != 2 {
+		t.Errorf("expected the resolver to be called on each context, got %v", client.loads)
+	}
+}
+
+// TestResolverCacheExpiration checks if the expired entries aren't returned.
+func TestResolverCacheExpiration(t *testing.T) {
+	cache := newResolverCache(&config.ResolverCache{TTL: time.Millisecond, Size: 10})
+
+	cache.put("mykey", "myvalue")
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.get("mykey"); ok {
+		t.Error("the entry must be expired")
+	}
+}
+
+// TestResolverCacheEviction checks if the least recently used entry is evicted when the cache is full.
+func TestResolverCacheEviction(t *testing.T) {
+	cache := newResolverCache(&config.ResolverCache{TTL: time.Minute, Size: 2})
+
+	cache.put("first", 1)
+	cache.put("second", 2)
+	cache.get("first")
+	cache.put("third", 3)
+
+	if _, ok := cache.get("second"); ok {
+		t.Error("the least recently used entry must be evicted")
+	}
+
+	if _, ok := cache.get("first"); !ok {
+		t.Error("the recently used entry must be kept")
+	}
+
+	if _, ok := cache.get("third"); !ok {
+		t.Error("the new entry must be kept")
+	}
+}
+
+// MockHTTPClientProfile is a mock of a resolver bridge that answers a profile map. It's safe to be
+// called concurrently.
+type MockHTTPClientProfile struct {
+	http.Client
+	calls int32
+}
+
+// Do counts the call and answers the profile
+func (m *MockHTTPClientProfile) Do(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&m.calls, 1)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"context": {"profile": {"segment": "gold", "tags": ["a"]}}}`)),
+	}, nil
+}
+
+// TestResolverCacheCopies checks if the cached maps and slices aren't shared by the requests, so
+// concurrent evaluations can change them without a data race or leaking their changes.
+func TestResolverCacheCopies(t *testing.T) {
+	setupResolverCache(t, "profileresolver", &config.ResolverCache{TTL: time.Minute, Size: 10, Keys: []string{"id"}})
+
+	client := &MockHTTPClientProfile{}
+	Client = client
+
+	// changes the profile of the request that resolves it
+	load := func(i int) map[string]interface{} {
+		ctx := NewContextFromMap(map[string]interface{}{"id": "1"})
+		ctx.RegistryRemoteLoaded("profile", "profileresolver")
+		profile := ctx.GetEntry("profile").(map[string]interface{})
+		result := map[string]interface{}{"segment": profile["segment"], "tags": len(profile["tags"].([]interface{}))}
+
+		profile["segment"] = fmt.Sprintf("changed %d", i)
+		profile["tags"] = append(profile["tags"].([]interface{}), i)
+		ctx.GetMap("profile").AddItem("tags", i)
+		return result
+	}
+
+	load(0)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				load(i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	expected := map[string]interface{}{"segment": "gold", "tags": 1}
+	if got := load(3); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	if client.calls != 1 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1, client.calls)
+	}
+}
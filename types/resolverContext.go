@@ -0,0 +1,46 @@
+package types
+
+import (
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// internalEntries are the context entries used by the ruller itself, that are never sent to the resolvers.
+var internalEntries = []string{"errors", "requiredParamErrors"}
+
+// RegistryRemoteLoadedWithContext registers a remote loaded param, like `RegistryRemoteLoadedWithFrom`,
+// declaring the context keys its resolver receives. Once a resolver has declared keys, on this method
+// or on `FEATWS_RULLER_RESOLVER_CONTEXT_KEYS`, only these keys are sent to it.
+func (c *Context) RegistryRemoteLoadedWithContext(param string, resolver string, from string, keys ...string) {
+	c.RegistryRemoteLoadedWithFrom(param, resolver, from)
+
+	if c.ResolverContextKeys == nil {
+		c.ResolverContextKeys = make(map[string][]string)
+	}
+	c.ResolverContextKeys[resolver] = append(c.ResolverContextKeys[resolver], keys...)
+}
+
+// resolverEntries returns the entries of the context sent to a resolver: the keys declared for the
+// resolver or, when there aren't declared keys, all the entries except the internal ones.
+func (c *Context) resolverEntries(resolver string, entries map[string]interface{}) map[string]interface{} {
+	declared := []string{}
+	declared = append(declared, config.GetConfig().ResolverContextKeys[resolver]...)
+	declared = append(declared, c.ResolverContextKeys[resolver]...)
+
+	filtered := make(map[string]interface{}, len(entries))
+	if len(declared) > 0 {
+		for _, key := range declared {
+			if value, ok := entries[key]; ok {
+				filtered[key] = value
+			}
+		}
+		return filtered
+	}
+
+	for key, value := range entries {
+		filtered[key] = value
+	}
+	for _, key := range internalEntries {
+		delete(filtered, key)
+	}
+	return filtered
+}
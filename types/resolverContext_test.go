@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// sentContext decodes the context sent on a request to the resolver bridge.
+func sentContext(t *testing.T, req *http.Request) map[string]interface{} {
+	body, err := req.GetBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := resolveInputV1{}
+	err = json.NewDecoder(body).Decode(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input.Context
+}
+
+// TestResolverContextDeclared checks if only the declared context keys are sent to a resolver.
+func TestResolverContextDeclared(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.ResolverContextKeys = map[string][]string{"customer": {"branch"}}
+	defer config.LoadConfig()
+
+	client := &MockHTTPClientRouting{}
+	Client = client
+
+	ctx := NewContextFromMap(map[string]interface{}{"branch": "0001", "account": "123", "document": "secret"})
+	ctx.RegistryRemoteLoaded("age", "customer")
+	ctx.RegistryRemoteLoadedWithContext("balance", "account", "", "branch", "account")
+	ctx.GetEntry("age")
+	ctx.GetEntry("balance")
+
+	expected := map[string]interface{}{"branch": "0001"}
+	if got := sentContext(t, client.requests[0]); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	expected = map[string]interface{}{"branch": "0001", "account": "123"}
+	if got := sentContext(t, client.requests[1]); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
+
+// TestResolverContextInternalEntries checks if the errors aren't sent to the resolvers without
+// declared keys.
+func TestResolverContextInternalEntries(t *testing.T) {
+	client := &MockHTTPClientRouting{}
+	Client = client
+
+	ctx := NewContextFromMap(map[string]interface{}{"branch": "0001"})
+	ctx.RegistryRequiredParams("missing")
+	ctx.RegistryRemoteLoaded("age", "customer")
+	ctx.GetEntry("age")
+
+	expected := map[string]interface{}{"branch": "0001"}
+	if got := sentContext(t, client.requests[0]); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
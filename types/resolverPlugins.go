@@ -0,0 +1,232 @@
+package types
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ContextualResolver is an optional interface of a Resolver that needs the entries of the context to
+// resolve a param, like a lookup table keyed by a context param.
+//
+// Property:
+//   - resolveWith: takes the entries of the context, the name of the resolver and the param, and returns the resolved value.
+type ContextualResolver interface {
+	resolveWith(entries map[string]interface{}, resolver string, param string) interface{}
+}
+
+// ResolverFunc adapts a function to a Resolver, so the resolvers implemented outside this package can
+// be registered as plugins. The function receives the entries of the context and the param to be
+// resolved, and panics to report a failure, like the resolver bridge.
+type ResolverFunc func(entries map[string]interface{}, param string) interface{}
+
+// resolve calls the function without the entries of the context
+func (f ResolverFunc) resolve(resolver string, param string) interface{} {
+	return f(nil, param)
+}
+
+// resolveWith calls the function with the entries of the context
+func (f ResolverFunc) resolveWith(entries map[string]interface{}, resolver string, param string) interface{} {
+	return f(entries, param)
+}
+
+// ConstantResolver is a Resolver that answers the params of a resolver from a constant map.
+type ConstantResolver map[string]interface{}
+
+// resolve returns the value of the param, panicking if it isn't defined.
+func (r ConstantResolver) resolve(resolver string, param string) interface{} {
+	value, ok := r[param]
+	if !ok {
+		log.Panicf("the param %s it's not defined on resolver %s", param, resolver)
+	}
+	return value
+}
+
+// LookupTableResolver is a Resolver that answers the params from the row of a table whose key is the
+// value of a context param. Each column of the row is a param of the resolver.
+//
+// Property:
+//   - Key: the context param whose value identifies the row.
+//   - Rows: the rows of the table by key.
+type LookupTableResolver struct {
+	Key  string
+	Rows map[string]map[string]interface{}
+}
+
+// resolve can't find the row without the entries of the context, so it always panics.
+func (r *LookupTableResolver) resolve(resolver string, param string) interface{} {
+	return r.resolveWith(nil, resolver, param)
+}
+
+// resolveWith returns the column of the row identified by the key param of the context, or nil when
+// there isn't such row. It panics when the context hasn't the key param.
+func (r *LookupTableResolver) resolveWith(entries map[string]interface{}, resolver string, param string) interface{} {
+	key, ok := entries[r.Key]
+	if !ok || key == nil {
+		log.Panicf("the param %s is required by the resolver %s", r.Key, resolver)
+	}
+
+	row, ok := r.Rows[fmt.Sprintf("%v", key)]
+	if !ok {
+		return nil
+	}
+	return row[param]
+}
+
+// LoadLookupTable loads a LookupTableResolver from a CSV file, with a header line, or from a JSON file
+// with an array of objects. The rows are identified by the value of the key column.
+func LoadLookupTable(path string, key string) (*LookupTableResolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err = readCSVRecords(file)
+	case ".json":
+		err = json.NewDecoder(file).Decode(&records)
+	default:
+		err = fmt.Errorf("unsupported lookup table format: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table := &LookupTableResolver{
+		Key:  key,
+		Rows: make(map[string]map[string]interface{}, len(records)),
+	}
+	for i, record := range records {
+		value, ok := record[key]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("the row %d of %s hasn't the key column %s", i+1, path, key)
+		}
+		table.Rows[fmt.Sprintf("%v", value)] = record
+	}
+	return table, nil
+}
+
+// readCSVRecords reads the lines of a CSV file as maps of values by the columns of the header line.
+func readCSVRecords(r io.Reader) ([]map[string]interface{}, error) {
+	lines, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	header := lines[0]
+	records := make([]map[string]interface{}, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		record := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			record[column] = line[i]
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+var resolverPluginsMutex sync.RWMutex
+
+// resolverPlugins are the in-process resolvers by resolver name.
+var resolverPlugins = make(map[string]Resolver)
+
+// RegisterResolver registers an in-process resolver. The remote loaded params of this resolver are
+// resolved by it instead of the resolver bridge.
+func RegisterResolver(name string, resolver Resolver) {
+	resolverPluginsMutex.Lock()
+	defer resolverPluginsMutex.Unlock()
+
+	resolverPlugins[name] = resolver
+}
+
+// UnregisterResolver removes an in-process resolver, so its params go back to the resolver bridge.
+func UnregisterResolver(name string) {
+	resolverPluginsMutex.Lock()
+	defer resolverPluginsMutex.Unlock()
+
+	delete(resolverPlugins, name)
+}
+
+// getResolverPlugin returns the in-process resolver registered with the name, if any.
+func getResolverPlugin(name string) (Resolver, bool) {
+	resolverPluginsMutex.RLock()
+	defer resolverPluginsMutex.RUnlock()
+
+	resolver, ok := resolverPlugins[name]
+	return resolver, ok
+}
+
+// resolveAllWithPlugin resolves each param with an in-process resolver.
+func resolveAllWithPlugin(plugin Resolver, entries map[string]interface{}, resolver string, params []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(params))
+	for _, param := range params {
+		if contextual, ok := plugin.(ContextualResolver); ok {
+			values[param] = contextual.resolveWith(entries, resolver, param)
+			continue
+		}
+		values[param] = plugin.resolve(resolver, param)
+	}
+	return values
+}
+
+// resolverPluginDefinition is the definition of a built-in resolver on the plugins file.
+//
+// Property:
+//   - Type: "constant" or "table".
+//   - Values: the values by param of a constant resolver.
+//   - Path: the CSV or JSON file of a table resolver, relative to the plugins file.
+//   - Key: the context param that identifies the row of a table resolver.
+type resolverPluginDefinition struct {
+	Type   string                 `json:"type"`
+	Values map[string]interface{} `json:"values"`
+	Path   string                 `json:"path"`
+	Key    string                 `json:"key"`
+}
+
+// LoadResolverPlugins registers the built-in resolvers defined on a JSON file, by resolver name.
+func LoadResolverPlugins(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	definitions := make(map[string]resolverPluginDefinition)
+	err = json.Unmarshal(data, &definitions)
+	if err != nil {
+		return fmt.Errorf("error on decode the resolver plugins: %w", err)
+	}
+
+	for name, definition := range definitions {
+		switch definition.Type {
+		case "constant":
+			RegisterResolver(name, ConstantResolver(definition.Values))
+		case "table":
+			tablePath := definition.Path
+			if !filepath.IsAbs(tablePath) {
+				tablePath = filepath.Join(filepath.Dir(path), tablePath)
+			}
+			table, err := LoadLookupTable(tablePath, definition.Key)
+			if err != nil {
+				return fmt.Errorf("error on load the table of the resolver %s: %w", name, err)
+			}
+			RegisterResolver(name, table)
+		default:
+			return fmt.Errorf("unknown type '%s' of the resolver %s", definition.Type, name)
+		}
+		log.Infof("Registered the in-process resolver %s", name)
+	}
+	return nil
+}
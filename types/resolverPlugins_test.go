@@ -0,0 +1,112 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResolverPluginConstant checks if a registered constant resolver is used instead of the resolver bridge.
+func TestResolverPluginConstant(t *testing.T) {
+	RegisterResolver("constantresolver", ConstantResolver{"max": 10})
+	defer UnregisterResolver("constantresolver")
+
+	client := &MockHTTPClientBatch{}
+	Client = client
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("max", "constantresolver")
+	ctx.RegistryRemoteLoaded("other", "bridgeresolver")
+
+	if got := ctx.GetInt("max"); got != 10 {
+		t.Errorf("Test Fail, we want %v, we got %v", 10, got)
+	}
+
+	if len(client.loads) != 0 {
+		t.Errorf("the resolver bridge must not be called, got %v", client.loads)
+	}
+
+	if got := ctx.GetString("other"); got != "value of other" {
+		t.Errorf("Test Fail, we want %v, we got %v", "value of other", got)
+	}
+}
+
+// TestResolverPluginFunc checks if a function resolver receives the entries of the context.
+func TestResolverPluginFunc(t *testing.T) {
+	RegisterResolver("funcresolver", ResolverFunc(func(entries map[string]interface{}, param string) interface{} {
+		return entries["a"].(float64) + entries["b"].(float64)
+	}))
+	defer UnregisterResolver("funcresolver")
+
+	ctx := NewContextFromMap(map[string]interface{}{"a": 1.5, "b": 2.0})
+	ctx.RegistryRemoteLoaded("sum", "funcresolver")
+
+	if got := ctx.GetFloat("sum"); got != 3.5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 3.5, got)
+	}
+}
+
+// TestLoadResolverPlugins checks if the lookup tables and the constant maps are loaded from the
+// plugins file.
+func TestLoadResolverPlugins(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"plugins.json": `{
+			"branches": {"type": "table", "path": "branches.csv", "key": "branch"},
+			"segments": {"type": "table", "path": "segments.json", "key": "segment"},
+			"limits": {"type": "constant", "values": {"max": 10}}
+		}`,
+		"branches.csv":  "branch,region\n0001,south\n0002,north\n",
+		"segments.json": `[{"segment": 1, "name": "retail"}]`,
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := LoadResolverPlugins(filepath.Join(dir, "plugins.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		UnregisterResolver("branches")
+		UnregisterResolver("segments")
+		UnregisterResolver("limits")
+	}()
+
+	ctx := NewContextFromMap(map[string]interface{}{"branch": "0002", "segment": 1})
+	ctx.RegistryRemoteLoaded("region", "branches")
+	ctx.RegistryRemoteLoadedWithFrom("segmentName", "segments", "name")
+	ctx.RegistryRemoteLoaded("max", "limits")
+
+	if got := ctx.GetString("region"); got != "north" {
+		t.Errorf("Test Fail, we want %v, we got %v", "north", got)
+	}
+
+	if got := ctx.GetString("segmentName"); got != "retail" {
+		t.Errorf("Test Fail, we want %v, we got %v", "retail", got)
+	}
+
+	if got := ctx.GetInt("max"); got != 10 {
+		t.Errorf("Test Fail, we want %v, we got %v", 10, got)
+	}
+}
+
+// TestLookupTableMissingKey checks if an error is recorded when the context hasn't the key param.
+func TestLookupTableMissingKey(t *testing.T) {
+	RegisterResolver("tableresolver", &LookupTableResolver{Key: "branch", Rows: map[string]map[string]interface{}{}})
+	defer UnregisterResolver("tableresolver")
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("region", "tableresolver")
+	ctx.load("region")
+
+	got := ctx.GetMap("errors").GetSlice("region")[0]
+	expected := "the param branch is required by the resolver tableresolver"
+
+	if got != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
@@ -0,0 +1,192 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The protocols of the resolver bridge.
+const (
+	ResolverProtocolV1 = "v1"
+	ResolverProtocolV2 = "v2"
+)
+
+// The status of a param on the v2 protocol.
+const (
+	resolveStatusOK    = "ok"
+	resolveStatusError = "error"
+)
+
+// paramError is the value of a param that the resolver failed to resolve while the others of the same
+// call succeeded. The failure policy of the param is applied when it's loaded.
+type paramError struct {
+	message string
+}
+
+// Error returns the message of the failure
+func (e *paramError) Error() string {
+	return e.message
+}
+
+// resolveParamV2 is the result of a param on the v2 protocol.
+//
+// Property:
+//   - Status: "ok" when the param was resolved or "error" when it failed.
+//   - Value: the resolved value.
+//   - Type: the type of the value: "string", "int", "float", "bool", "decimal", "date", "datetime", "list" or "map". When it's empty, the value is kept as decoded from JSON.
+//   - Error: the message of the failure when the status is "error".
+//   - MaxAge: the time, in seconds, the value can be cached. It overrides the MaxAge of the response.
+type resolveParamV2 struct {
+	Status string      `json:"status"`
+	Value  interface{} `json:"value,omitempty"`
+	Type   string      `json:"type,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	MaxAge *int64      `json:"maxAge,omitempty"`
+}
+
+// resolveOutputV2 is the response of the resolver bridge on the v2 protocol. The params are resolved
+// independently, so some of them can fail while the others succeed.
+//
+// Property:
+//   - Params: the result of each loaded param.
+//   - MaxAge: the time, in seconds, the values of the response can be cached.
+//   - Error: the message of a failure of the whole call.
+type resolveOutputV2 struct {
+	Params map[string]resolveParamV2 `json:"params,omitempty"`
+	MaxAge *int64                    `json:"maxAge,omitempty"`
+	Error  string                    `json:"error,omitempty"`
+}
+
+// decodeOutputV2 decodes a response of the v2 protocol keeping its numbers as `json.Number`, so the
+// ints and the decimals are converted from their exact representation.
+func decodeOutputV2(data []byte) (*resolveOutputV2, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	output := &resolveOutputV2{}
+	err := decoder.Decode(output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// decode returns the typed values and the max-age hints by param. The params missing on the response
+// are resolved as nil, like on the v1 protocol, and the failed ones as a paramError.
+func (o *resolveOutputV2) decode(params []string) (map[string]interface{}, map[string]time.Duration) {
+	if o.Error != "" {
+		log.WithField("error", o.Error).Panic(o.Error)
+	}
+
+	values := make(map[string]interface{}, len(params))
+	maxAges := make(map[string]time.Duration)
+	for _, param := range params {
+		result, ok := o.Params[param]
+		if !ok {
+			values[param] = nil
+			continue
+		}
+
+		if result.Status == resolveStatusError {
+			values[param] = &paramError{message: result.Error}
+			continue
+		}
+
+		if result.Status != resolveStatusOK {
+			values[param] = &paramError{message: fmt.Sprintf("unknown status '%s' of the param %s", result.Status, param)}
+			continue
+		}
+
+		value, err := decodeTypedValue(result.Type, result.Value)
+		if err != nil {
+			values[param] = &paramError{message: err.Error()}
+			continue
+		}
+		values[param] = value
+
+		maxAge := o.MaxAge
+		if result.MaxAge != nil {
+			maxAge = result.MaxAge
+		}
+		if maxAge != nil {
+			maxAges[param] = time.Duration(*maxAge) * time.Second
+		}
+	}
+	return values, maxAges
+}
+
+// decodeTypedValue converts a value decoded from JSON, with its numbers as `json.Number`, to the Go type
+// of its declared type. The decimal values are kept as their string representation, so they don't lose
+// precision, and an int with a fractional part is an error.
+func decodeTypedValue(typ string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch typ {
+	case "":
+		return value, nil
+	case "string":
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case "int":
+		if v, ok := value.(json.Number); ok {
+			return decodeInt(v)
+		}
+	case "float":
+		if v, ok := value.(json.Number); ok {
+			return v.Float64()
+		}
+	case "bool":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case "decimal":
+		switch v := value.(type) {
+		case string, json.Number:
+			str := fmt.Sprintf("%v", v)
+			if _, err := ParseDecimal(str); err != nil {
+				return nil, fmt.Errorf("the value %v isn't a decimal", value)
+			}
+			return str, nil
+		}
+	case "date":
+		if v, ok := value.(string); ok {
+			return time.Parse("2006-01-02", v)
+		}
+	case "datetime":
+		if v, ok := value.(string); ok {
+			return time.Parse(time.RFC3339, v)
+		}
+	case "list":
+		if v, ok := value.([]interface{}); ok {
+			return v, nil
+		}
+	case "map":
+		if v, ok := value.(map[string]interface{}); ok {
+			return v, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown type '%s'", typ)
+	}
+	return nil, fmt.Errorf("the value %v isn't a %s", value, typ)
+}
+
+// decodeInt converts a JSON number to an int, accepting the integral values written as floats, like
+// 2.0 or 1e3, and rejecting the ones with a fractional part or out of the range of an int64.
+func decodeInt(value json.Number) (interface{}, error) {
+	if v, err := value.Int64(); err == nil {
+		return v, nil
+	}
+
+	d, err := ParseDecimal(value.String())
+	if err != nil || !d.Equal(NewDecimalFromInt(d.IntPart())) {
+		return nil, fmt.Errorf("the value %v isn't a int", value)
+	}
+	return d.IntPart(), nil
+}
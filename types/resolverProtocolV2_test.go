@@ -0,0 +1,192 @@
+package types
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// MockHTTPClientV2 is a mock of a resolver bridge on the v2 protocol that answers a fixed response.
+//
+// Property:
+//   - response: the body of the responses.
+//   - requests: the received requests, in the order of the calls.
+type MockHTTPClientV2 struct {
+	http.Client
+	response string
+	requests []*http.Request
+}
+
+// Do records the request and answers the fixed response
+func (m *MockHTTPClientV2) Do(req *http.Request) (*http.Response, error) {
+	m.requests = append(m.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(m.response)),
+	}, nil
+}
+
+// mockResolverResponseV2 is a response of the v2 protocol with typed values, a failed param and cache hints.
+const mockResolverResponseV2 = `{
+	"maxAge": 60,
+	"params": {
+		"age": {"status": "ok", "value": 21, "type": "int"},
+		"birth": {"status": "ok", "value": "2000-01-02", "type": "date"},
+		"limit": {"status": "ok", "value": "1234.5678901234567890", "type": "decimal", "maxAge": 0},
+		"cards": {"status": "ok", "value": ["gold", "black"], "type": "list"},
+		"score": {"status": "error", "error": "score unavailable"}
+	}
+}`
+
+// setupResolverProtocolV2 makes the default resolver bridge use the v2 protocol for a test.
+func setupResolverProtocolV2(t *testing.T) *MockHTTPClientV2 {
+	cfg := config.GetConfig()
+	cfg.ResolverBridgeURL = "http://bridge"
+	cfg.ResolverBridgeProtocol = ResolverProtocolV2
+	t.Cleanup(func() {
+		config.LoadConfig()
+	})
+
+	client := &MockHTTPClientV2{response: mockResolverResponseV2}
+	Client = client
+	return client
+}
+
+// TestResolverProtocolV2 checks if the typed values are stored with their declared types and the
+// failed param gets its failure policy while the others succeed.
+func TestResolverProtocolV2(t *testing.T) {
+	client := setupResolverProtocolV2(t)
+
+	ctx := NewContext()
+	for _, param := range []string{"age", "birth", "limit", "cards"} {
+		ctx.RegistryRemoteLoaded(param, "customer")
+	}
+	ctx.RegistryRemoteLoadedWithFallback("score", "customer", 500)
+
+	if got := ctx.Get("age"); got != int64(21) {
+		t.Errorf("Test Fail, we want %v, we got %v", int64(21), got)
+	}
+
+	expectedBirth := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	if got, ok := ctx.Get("birth").(time.Time); !ok || !got.Equal(expectedBirth) {
+		t.Errorf("Test Fail, we want %v, we got %v", expectedBirth, ctx.Get("birth"))
+	}
+
+	if got := ctx.Get("limit"); got != "1234.5678901234567890" {
+		t.Errorf("Test Fail, we want %v, we got %v", "1234.5678901234567890", got)
+	}
+
+	if got := ctx.GetSlice("cards"); len(got) != 2 {
+		t.Errorf("Test Fail, we want %v, we got %v", 2, len(got))
+	}
+
+	if got := ctx.GetInt("score"); got != 500 {
+		t.Errorf("Test Fail, we want %v, we got %v", 500, got)
+	}
+
+	if got := ctx.GetMap("errors").GetSlice("score")[0]; got != "score unavailable" {
+		t.Errorf("Test Fail, we want %v, we got %v", "score unavailable", got)
+	}
+
+	if len(client.requests) != 1 || client.requests[0].URL.String() != "http://bridge/api/v2/resolve/customer" {
+		t.Errorf("expected a single call to the v2 endpoint, got %v", client.requests)
+	}
+}
+
+// TestResolverProtocolV2CacheHints checks if the max-age hints limit the caching of the values.
+func TestResolverProtocolV2CacheHints(t *testing.T) {
+	client := setupResolverProtocolV2(t)
+	setupResolverCache(t, "hintedresolver", &config.ResolverCache{TTL: time.Minute, Size: 10})
+
+	for i := 0; i < 2; i++ {
+		ctx := NewContext()
+		ctx.RegistryRemoteLoaded("age", "hintedresolver")
+		ctx.GetEntry("age")
+	}
+
+	if len(client.requests) != 1 {
+		t.Errorf("expected the value with max-age to be cached, got %d calls", len(client.requests))
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx := NewContext()
+		ctx.RegistryRemoteLoaded("limit", "hintedresolver")
+		ctx.GetEntry("limit")
+	}
+
+	if len(client.requests) != 3 {
+		t.Errorf("expected the value with max-age 0 not to be cached, got %d calls", len(client.requests))
+	}
+}
+
+// TestDecodeTypedValueMismatch checks if a value that doesn't match its declared type is an error.
+func TestDecodeTypedValueMismatch(t *testing.T) {
+	_, err := decodeTypedValue("int", "not a number")
+	if err == nil {
+		t.Error("expected an error on a value that isn't an int")
+	}
+}
+
+// TestResolverProtocolV2Numbers checks if the ints and the decimals sent as JSON numbers are decoded
+// without losing precision.
+func TestResolverProtocolV2Numbers(t *testing.T) {
+	client := setupResolverProtocolV2(t)
+	client.response = `{"params": {
+		"id": {"status": "ok", "value": 9007199254740993, "type": "int"},
+		"limit": {"status": "ok", "value": 12345678901234567.89, "type": "decimal"},
+		"half": {"status": "ok", "value": 1.5, "type": "int"}
+	}}`
+
+	ctx := NewContext()
+	for _, param := range []string{"id", "limit", "half"} {
+		ctx.RegistryRemoteLoaded(param, "customer")
+	}
+
+	if got := ctx.Get("half"); got != nil {
+		t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+	}
+	if got := ctx.GetMap("errors").GetSlice("half"); len(got) != 1 || got[0] != "the value 1.5 isn't a int" {
+		t.Errorf("Test Fail, we want %v, we got %v", "the value 1.5 isn't a int", got)
+	}
+
+	if got := ctx.Get("id"); got != int64(9007199254740993) {
+		t.Errorf("Test Fail, we want %v, we got %v", int64(9007199254740993), got)
+	}
+
+	if got := ctx.Get("limit"); got != "12345678901234567.89" {
+		t.Errorf("Test Fail, we want %v, we got %v", "12345678901234567.89", got)
+	}
+}
+
+// TestDecodeTypedValueNumbers checks the conversion of the JSON numbers to the declared types.
+func TestDecodeTypedValueNumbers(t *testing.T) {
+	tests := []struct {
+		typ      string
+		value    json.Number
+		expected interface{}
+	}{
+		{"int", "42", int64(42)},
+		{"int", "2.0", int64(2)},
+		{"int", "1e3", int64(1000)},
+		{"float", "0.1", 0.1},
+		{"decimal", "0.10", "0.10"},
+	}
+
+	for _, test := range tests {
+		got, err := decodeTypedValue(test.typ, test.value)
+		if err != nil || got != test.expected {
+			t.Errorf("Test Fail on %s %s, we want %v, we got %v (%v)", test.typ, test.value, test.expected, got, err)
+		}
+	}
+
+	for _, value := range []json.Number{"1.5", "1e-3", "9223372036854775808"} {
+		if _, err := decodeTypedValue("int", value); err == nil {
+			t.Errorf("expected an error on the int %s", value)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package types
 
 // Result its used to store features in end rule assertions
+//
+// Property:
+//   - assignments: the experiments assigned on the evaluation.
 type Result struct {
 	TypedMap
+	assignments []*Assignment
 }
 
 // NewResult method create a new Result
@@ -0,0 +1,310 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// errorRecorder is implemented by the getters that record the conversion errors of the safe getters,
+// like the `Context` does on its `errors` entry.
+type errorRecorder interface {
+	recordConversionError(param string, err error)
+}
+
+// ConversionError is the error of a param whose value can't be converted to the type of the getter.
+//
+// Property:
+//   - Param: the name of the param.
+//   - Type: the type of the getter.
+//   - Value: the value of the param.
+type ConversionError struct {
+	Param string
+	Type  string
+	Value interface{}
+}
+
+// Error returns the message of the conversion error.
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("the param %s with the value %v can't be converted to %s", e.Param, e.Value, e.Type)
+}
+
+// recordConversionError records the conversion error on the `errors` entry of the context, so the
+// evaluation goes on with the default value of the getter. The same error of a param is recorded once,
+// since the getters of the `when` of the rules are called again on each cycle of the evaluation, while
+// the other errors of the param, like the failure of its resolver, are kept.
+func (c *Context) recordConversionError(param string, err error) {
+	if c.Has("errors") {
+		recorded, _ := c.GetMap("errors").GetEntry(param).([]interface{})
+		for _, item := range recorded {
+			if item == err.Error() {
+				return
+			}
+		}
+	}
+	c.addError("errors", param, err)
+}
+
+// convert gets the value of the param converted by the function. It returns false when the param is
+// missing or can't be converted, recording the conversion errors when the map has an errorRecorder.
+func (c *TypedMap) convert(param string, typeName string, converter func(interface{}) (interface{}, bool)) (interface{}, bool) {
+	value := c.Get(param)
+	if value == nil {
+		return nil, false
+	}
+
+	converted, ok := converter(value)
+	if !ok {
+		c.conversionFailed(param, typeName, value)
+		return nil, false
+	}
+	return converted, true
+}
+
+// conversionFailed records the conversion error of the param when the map has an errorRecorder,
+// returning false when it hasn't one.
+func (c *TypedMap) conversionFailed(param string, typeName string, value interface{}) bool {
+	recorder, ok := c.Getter.(errorRecorder)
+	if ok {
+		recorder.recordConversionError(param, &ConversionError{Param: param, Type: typeName, Value: value})
+	}
+	return ok
+}
+
+// GetStringOr gets the param as a string, or the default value when it's missing or can't be converted.
+func (c *TypedMap) GetStringOr(param string, defaultValue string) string {
+	value, ok := c.convert(param, "string", toString)
+	if !ok {
+		return defaultValue
+	}
+	return value.(string)
+}
+
+// GetIntOr gets the param as an int, or the default value when it's missing or can't be converted.
+func (c *TypedMap) GetIntOr(param string, defaultValue int64) int64 {
+	value, ok := c.convert(param, "int", toInt64)
+	if !ok {
+		return defaultValue
+	}
+	return value.(int64)
+}
+
+// GetFloatOr gets the param as a float, or the default value when it's missing or can't be converted.
+func (c *TypedMap) GetFloatOr(param string, defaultValue float64) float64 {
+	value, ok := c.convert(param, "float", toFloat64)
+	if !ok {
+		return defaultValue
+	}
+	return value.(float64)
+}
+
+// GetBoolOr gets the param as a bool, or the default value when it's missing or can't be converted.
+func (c *TypedMap) GetBoolOr(param string, defaultValue bool) bool {
+	value, ok := c.convert(param, "bool", toBool)
+	if !ok {
+		return defaultValue
+	}
+	return value.(bool)
+}
+
+// GetSliceOr gets the param as a slice, or the default value when it's missing or isn't a slice.
+func (c *TypedMap) GetSliceOr(param string, defaultValue []interface{}) []interface{} {
+	value, ok := c.convert(param, "slice", toSlice)
+	if !ok {
+		return defaultValue
+	}
+	return value.([]interface{})
+}
+
+// GetMapOr gets the param as a map, or the default value when it's missing or isn't a map.
+func (c *TypedMap) GetMapOr(param string, defaultValue *TypedMap) *TypedMap {
+	value, ok := c.convert(param, "map", toTypedMap)
+	if !ok {
+		return defaultValue
+	}
+	return value.(*TypedMap)
+}
+
+// TryGetString gets the param as a string, or an empty string when it's missing or can't be converted.
+func (c *TypedMap) TryGetString(param string) string {
+	return c.GetStringOr(param, "")
+}
+
+// TryGetInt gets the param as an int, or zero when it's missing or can't be converted.
+func (c *TypedMap) TryGetInt(param string) int64 {
+	return c.GetIntOr(param, 0)
+}
+
+// TryGetFloat gets the param as a float, or zero when it's missing or can't be converted.
+func (c *TypedMap) TryGetFloat(param string) float64 {
+	return c.GetFloatOr(param, 0)
+}
+
+// TryGetBool gets the param as a bool, or false when it's missing or can't be converted.
+func (c *TypedMap) TryGetBool(param string) bool {
+	return c.GetBoolOr(param, false)
+}
+
+// TryGetSlice gets the param as a slice, or an empty slice when it's missing or isn't a slice.
+func (c *TypedMap) TryGetSlice(param string) []interface{} {
+	return c.GetSliceOr(param, []interface{}{})
+}
+
+// TryGetMap gets the param as a map, or an empty map when it's missing or isn't a map.
+func (c *TypedMap) TryGetMap(param string) *TypedMap {
+	return c.GetMapOr(param, NewTypedMap())
+}
+
+// toString converts a value to a string. The numbers are formatted without exponent.
+func toString(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case Decimal:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), true
+	}
+
+	kind := reflect.ValueOf(value).Kind()
+	if kind == reflect.Map || kind == reflect.Slice || kind == reflect.Array || kind == reflect.Ptr {
+		return nil, false
+	}
+	return fmt.Sprintf("%v", value), true
+}
+
+// toInt64 converts a number or a numeric string to an int. The fractional part of the floats is
+// truncated.
+func toInt64(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return parseInt(v.String())
+	case string:
+		return parseInt(v)
+	case Decimal:
+		return v.IntPart(), true
+	case float64:
+		return int64(v), true
+	case float32:
+		return int64(v), true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return nil, false
+}
+
+// parseInt parses an int from a string, accepting a float string with its fractional part truncated.
+func parseInt(str string) (interface{}, bool) {
+	str = strings.TrimSpace(str)
+	if value, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return value, true
+	}
+	if value, err := strconv.ParseFloat(str, 64); err == nil {
+		return int64(value), true
+	}
+	return nil, false
+}
+
+// toFloat64 converts a number or a numeric string to a float.
+func toFloat64(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return parseFloat(v.String())
+	case string:
+		return parseFloat(v)
+	case Decimal:
+		return v.Float64(), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+
+	if v, ok := toInt64(value); ok {
+		return float64(v.(int64)), true
+	}
+	return nil, false
+}
+
+// parseFloat parses a float from a string.
+func parseFloat(str string) (interface{}, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// toBool converts a bool, a bool string, like "true", "1" or "F", or a number, true when isn't zero,
+// to a bool.
+func toBool(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, false
+		}
+		return parsed, true
+	}
+
+	if v, ok := toFloat64(value); ok {
+		return v.(float64) != 0, true
+	}
+	return nil, false
+}
+
+// toSlice converts a slice or an array to a slice of interfaces.
+func toSlice(value interface{}) (interface{}, bool) {
+	if v, ok := value.([]interface{}); ok {
+		return v, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result, true
+}
+
+// toTypedMap converts a map or a TypedMap to a TypedMap.
+func toTypedMap(value interface{}) (interface{}, bool) {
+	if v, ok := value.(*TypedMap); ok {
+		return v, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	result := NewTypedMap()
+	for _, key := range rv.MapKeys() {
+		result.Put(fmt.Sprintf("%v", key.Interface()), rv.MapIndex(key).Interface())
+	}
+	return result, true
+}
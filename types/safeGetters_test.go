@@ -0,0 +1,191 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestGetOr checks if the safe getters convert the values and get the default value when the param
+// is missing or can't be converted.
+func TestGetOr(t *testing.T) {
+	tm := NewTypedMapFromMap(map[string]interface{}{
+		"int":        json.Number("42"),
+		"intString":  " 7 ",
+		"floatInt":   "7.9",
+		"float":      json.Number("1.5"),
+		"boolString": "true",
+		"boolNumber": 0,
+		"text":       "abc",
+		"number":     1.25,
+		"list":       []string{"a", "b"},
+		"map":        map[string]interface{}{"a": 1},
+	})
+
+	tests := []struct {
+		got      interface{}
+		expected interface{}
+	}{
+		{tm.GetIntOr("int", -1), int64(42)},
+		{tm.GetIntOr("intString", -1), int64(7)},
+		{tm.GetIntOr("floatInt", -1), int64(7)},
+		{tm.GetIntOr("text", -1), int64(-1)},
+		{tm.GetIntOr("missing", -1), int64(-1)},
+		{tm.GetFloatOr("float", -1), 1.5},
+		{tm.GetFloatOr("int", -1), float64(42)},
+		{tm.GetFloatOr("text", -1), float64(-1)},
+		{tm.GetBoolOr("boolString", false), true},
+		{tm.GetBoolOr("boolNumber", true), false},
+		{tm.GetBoolOr("text", true), true},
+		{tm.GetStringOr("number", "none"), "1.25"},
+		{tm.GetStringOr("int", "none"), "42"},
+		{tm.GetStringOr("missing", "none"), "none"},
+		{tm.GetStringOr("map", "none"), "none"},
+		{tm.GetSliceOr("list", nil), []interface{}{"a", "b"}},
+		{tm.GetSliceOr("text", nil), []interface{}(nil)},
+		{tm.GetMapOr("map", nil).GetInt("a"), int64(1)},
+		{tm.GetMapOr("text", nil), (*TypedMap)(nil)},
+		{tm.TryGetInt("text"), int64(0)},
+		{tm.TryGetFloat("missing"), float64(0)},
+		{tm.TryGetBool("missing"), false},
+		{tm.TryGetString("missing"), ""},
+		{tm.TryGetSlice("missing"), []interface{}{}},
+		{len(tm.TryGetMap("missing").GetEntries()), 0},
+	}
+
+	for i, test := range tests {
+		if !reflect.DeepEqual(test.got, test.expected) {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+}
+
+// TestGetOrRecordsErrors checks if the conversion errors of the safe getters are recorded on the
+// errors of the context, while the missing params aren't.
+func TestGetOrRecordsErrors(t *testing.T) {
+	ctx := NewContextFromMap(map[string]interface{}{"age": "unknown"})
+
+	if got := ctx.TryGetInt("age"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+	if got := ctx.GetFloatOr("missing", 1.5); got != 1.5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1.5, got)
+	}
+
+	errs := ctx.GetMap("errors").GetEntries()
+	expected := map[string]interface{}{
+		"age": []interface{}{"the param age with the value unknown can't be converted to int"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, errs)
+	}
+}
+
+// TestGetOrRecordsErrorsOnce checks if each conversion error of a param is recorded once, even when its
+// getters are called again.
+func TestGetOrRecordsErrorsOnce(t *testing.T) {
+	ctx := NewContextFromMap(map[string]interface{}{"age": "unknown"})
+
+	for i := 0; i < 3; i++ {
+		ctx.TryGetInt("age")
+		ctx.GetFloatOr("age", 1.5)
+	}
+
+	errs := ctx.GetMap("errors").GetEntries()
+	expected := map[string]interface{}{
+		"age": []interface{}{
+			"the param age with the value unknown can't be converted to int",
+			"the param age with the value unknown can't be converted to float",
+		},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, errs)
+	}
+}
+
+// TestGetOrRecordsErrorsAfterLoadFailure checks if the conversion error of a param is recorded even
+// when the param already has the error of its resolver.
+func TestGetOrRecordsErrorsAfterLoadFailure(t *testing.T) {
+	ctx := NewContext()
+	ctx.Resolver = StubResolver{}
+	ctx.RegistryRemoteLoadedWithFallback("age", "customer", "unknown")
+
+	for i := 0; i < 2; i++ {
+		if got := ctx.TryGetInt("age"); got != 0 {
+			t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+		}
+	}
+
+	errs := ctx.GetMap("errors").GetSlice("age")
+	if len(errs) != 2 || errs[1] != "the param age with the value unknown can't be converted to int" {
+		t.Errorf("expected the load and the conversion errors, got %v", errs)
+	}
+}
+
+// TestLegacyGettersRecordErrors checks if the legacy getters of a context record the values that can't
+// be converted, evaluating to their zero values, instead of panicking or ignoring them.
+func TestLegacyGettersRecordErrors(t *testing.T) {
+	ctx := NewContextFromMap(map[string]interface{}{"text": "abc", "flag": false, "number": json.Number("1.5")})
+
+	if got := ctx.GetInt("text"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+	if got := ctx.GetInt("flag"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+	if got := ctx.GetFloat("text"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+	if got := ctx.GetFloat("flag"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+	if got := ctx.GetSlice("text"); got == nil || len(got) != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", []interface{}{}, got)
+	}
+	if got := ctx.GetMap("number").GetEntries(); len(got) != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", map[string]interface{}{}, got)
+	}
+	if got := ctx.GetFloat("number"); got != 1.5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 1.5, got)
+	}
+
+	errs := ctx.GetMap("errors").GetEntries()
+	expected := map[string]interface{}{
+		"text": []interface{}{
+			"the param text with the value abc can't be converted to int",
+			"the param text with the value abc can't be converted to float",
+			"the param text with the value abc can't be converted to slice",
+		},
+		"flag": []interface{}{
+			"the param flag with the value false can't be converted to int",
+			"the param flag with the value false can't be converted to float",
+		},
+		"number": []interface{}{"the param number with the value 1.5 can't be converted to map"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, errs)
+	}
+}
+
+// TestGetStringNil checks if a missing param is an empty string.
+func TestGetStringNil(t *testing.T) {
+	tm := NewTypedMap()
+	if got := tm.GetString("missing"); got != "" {
+		t.Errorf("Test Fail, we want %v, we got %v", "", got)
+	}
+}
+
+// TestGetJSONNumber checks if the getters handle the numbers decoded as json.Number.
+func TestGetJSONNumber(t *testing.T) {
+	tm := NewTypedMapFromMap(map[string]interface{}{"int": json.Number("12"), "float": json.Number("2.5")})
+
+	if got := tm.GetInt("int"); got != 12 {
+		t.Errorf("Test Fail, we want %v, we got %v", 12, got)
+	}
+	if got := tm.GetFloat("float"); got != 2.5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 2.5, got)
+	}
+	if got := tm.GetString("float"); got != "2.5" {
+		t.Errorf("Test Fail, we want %v, we got %v", "2.5", got)
+	}
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// StubResolver is a Resolver that answers the remote loaded params from an in-memory map instead of
+// calling the resolver bridge. The outer key is the resolver name and the inner key is the param
+// requested to this resolver (the `From` of the remote loaded param).
+type StubResolver map[string]map[string]interface{}
+
+// resolve returns the stubbed value of a param for the given resolver. It panics if the resolver or
+// the param isn't stubbed, the same way a resolver bridge failure does, so the error is recorded on
+// the context `errors` map by `loadImpl`.
+func (s StubResolver) resolve(resolver string, param string) interface{} {
+	values, ok := s[resolver]
+	if !ok {
+		log.Panicf("the resolver %s it's not stubbed", resolver)
+	}
+
+	value, ok := values[param]
+	if !ok {
+		log.Panicf("the param %s it's not stubbed on resolver %s", param, resolver)
+	}
+
+	return value
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+)
+
+// TestStubResolverLoad checks if a remote loaded param is answered by the stubbed values.
+func TestStubResolverLoad(t *testing.T) {
+	ctx := NewContext()
+	ctx.Resolver = StubResolver{
+		"myresolver": {"myfrom": "myresult"},
+	}
+
+	ctx.RegistryRemoteLoadedWithFrom("myRemoteParam", "myresolver", "myfrom")
+
+	got := ctx.GetString("myRemoteParam")
+	expected := "myresult"
+
+	if got != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
+
+// TestStubResolverNotStubbed checks if an error is recorded when the param isn't stubbed.
+func TestStubResolverNotStubbed(t *testing.T) {
+	ctx := NewContext()
+	ctx.Resolver = StubResolver{
+		"myresolver": {},
+	}
+
+	ctx.RegistryRemoteLoaded("myRemoteParam", "myresolver")
+	ctx.load("myRemoteParam")
+
+	got := ctx.GetMap("errors").GetSlice("myRemoteParam")[0]
+	expected := "the param myRemoteParam it's not stubbed on resolver myresolver"
+
+	if got != expected {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+}
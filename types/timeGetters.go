@@ -0,0 +1,131 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultDateLayouts are the layouts of the dates informed as strings tried after the configured ones.
+var defaultDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTime parses a date informed as a string with the layouts of `FEATWS_RULLER_DATE_LAYOUTS` or, when
+// none of them matches, as RFC3339 or "2006-01-02". The dates without a zone are on the time zone of
+// `FEATWS_RULLER_TIMEZONE`.
+func ParseTime(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	location := config.GetConfig().TimeLocation()
+
+	layouts := make([]string, 0, len(config.GetConfig().DateLayouts)+len(defaultDateLayouts))
+	layouts = append(layouts, config.GetConfig().DateLayouts...)
+	layouts = append(layouts, defaultDateLayouts...)
+
+	for _, layout := range layouts {
+		value, err := time.ParseInLocation(layout, str, location)
+		if err == nil {
+			return value, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("the date %s doesn't match any of the date layouts", str)
+}
+
+// toTime converts a time, a date string or a number of seconds since the Unix epoch to a time.
+func toTime(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return nil, false
+		}
+		return *v, true
+	case string:
+		parsed, err := ParseTime(v)
+		if err != nil {
+			return nil, false
+		}
+		return parsed, true
+	}
+
+	seconds, ok := toInt64(value)
+	if !ok {
+		return nil, false
+	}
+	return time.Unix(seconds.(int64), 0).In(config.GetConfig().TimeLocation()), true
+}
+
+// toDate converts a value to a time, like toTime, truncated to the start of its day.
+func toDate(value interface{}) (interface{}, bool) {
+	converted, ok := toTime(value)
+	if !ok {
+		return nil, false
+	}
+	return StartOfDay(converted.(time.Time)), true
+}
+
+// StartOfDay returns the start of the day of the time, on its location.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// GetTime method get a time entry of map. The entry can be a time, a date string or a number of seconds
+// since the Unix epoch. A missing entry is the zero time.
+func (c *TypedMap) GetTime(param string) time.Time {
+	value := c.Get(param)
+	if value == nil {
+		return time.Time{}
+	}
+
+	converted, ok := toTime(value)
+	if !ok {
+		log.Panic("It's not possible to recover this parameter as time")
+		panic("It's not possible to recover this parameter as time")
+	}
+	return converted.(time.Time)
+}
+
+// GetDate method get a time entry of map, like GetTime, truncated to the start of its day.
+func (c *TypedMap) GetDate(param string) time.Time {
+	value := c.GetTime(param)
+	if value.IsZero() {
+		return value
+	}
+	return StartOfDay(value)
+}
+
+// GetTimeOr gets the param as a time, or the default value when it's missing or can't be converted.
+func (c *TypedMap) GetTimeOr(param string, defaultValue time.Time) time.Time {
+	value, ok := c.convert(param, "time", toTime)
+	if !ok {
+		return defaultValue
+	}
+	return value.(time.Time)
+}
+
+// GetDateOr gets the param as a date, or the default value when it's missing or can't be converted.
+func (c *TypedMap) GetDateOr(param string, defaultValue time.Time) time.Time {
+	value, ok := c.convert(param, "date", toDate)
+	if !ok {
+		return defaultValue
+	}
+	return value.(time.Time)
+}
+
+// TryGetTime gets the param as a time, or the zero time when it's missing or can't be converted.
+func (c *TypedMap) TryGetTime(param string) time.Time {
+	return c.GetTimeOr(param, time.Time{})
+}
+
+// TryGetDate gets the param as a date, or the zero time when it's missing or can't be converted.
+func (c *TypedMap) TryGetDate(param string) time.Time {
+	return c.GetDateOr(param, time.Time{})
+}
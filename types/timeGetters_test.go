@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-ruller/config"
+)
+
+// TestGetTime checks if the times are parsed from the date strings, the times and the Unix timestamps.
+func TestGetTime(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	config.GetConfig().Location = saoPaulo
+	config.GetConfig().DateLayouts = []string{"02/01/2006"}
+	defer config.LoadConfig()
+
+	tm := NewTypedMapFromMap(map[string]interface{}{
+		"rfc3339":   "2024-03-10T15:30:00Z",
+		"date":      "2024-03-10",
+		"layout":    "10/03/2024",
+		"time":      time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC),
+		"timestamp": 1710084600,
+		"invalid":   "tomorrow",
+	})
+
+	tests := []struct {
+		got      time.Time
+		expected time.Time
+	}{
+		{tm.GetTime("rfc3339"), time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)},
+		{tm.GetTime("date"), time.Date(2024, 3, 10, 0, 0, 0, 0, saoPaulo)},
+		{tm.GetTime("layout"), time.Date(2024, 3, 10, 0, 0, 0, 0, saoPaulo)},
+		{tm.GetTime("time"), time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)},
+		{tm.GetTime("timestamp"), time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)},
+		{tm.GetDate("rfc3339"), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{tm.GetDate("timestamp"), time.Date(2024, 3, 10, 0, 0, 0, 0, saoPaulo)},
+		{tm.GetTime("missing"), time.Time{}},
+		{tm.GetTimeOr("invalid", time.Unix(0, 0)), time.Unix(0, 0)},
+		{tm.TryGetDate("invalid"), time.Time{}},
+	}
+
+	for i, test := range tests {
+		if !test.got.Equal(test.expected) {
+			t.Errorf("Test Fail on %d, we want %v, we got %v", i, test.expected, test.got)
+		}
+	}
+}
+
+// TestGetTimeWithPanic checks if GetTime panics when the param isn't a date.
+func TestGetTimeWithPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic on an invalid date")
+		}
+	}()
+
+	tm := NewTypedMapFromMap(map[string]interface{}{"invalid": "tomorrow"})
+	tm.GetTime("invalid")
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -47,9 +48,13 @@ func (c *TypedMap) Put(param string, value interface{}) {
 	c.interfaceMap[param] = value
 }
 
-// Has method verify if a param exists in map
+// Has method verify if a param exists in map. The param can also be a path on the nested entries, like
+// `customer.address.state` or `accounts[0].balance`.
 func (c *TypedMap) Has(param string) bool {
 	_, exists := c.interfaceMap[param]
+	if !exists && isPath(param) {
+		_, exists = c.lookupPath(param)
+	}
 	return exists
 }
 
@@ -65,30 +70,41 @@ func (c *TypedMap) GetEntry(param string) interface{} {
 	return value
 }
 
-// Get method get a generic entry of map
+// Get method get a generic entry of map. When there isn't an entry with the param as key, the param can
+// be a path on the nested maps and slices, like `customer.address.state` or `accounts[0].balance`, used
+// by all the typed getters.
 func (c *TypedMap) Get(param string) interface{} {
-	if c.Getter != nil {
-		return c.Getter.GetEntry(param)
+	value := c.lookupEntry(param)
+	if value == nil && isPath(param) {
+		value, _ = c.lookupPath(param)
 	}
-	return c.GetEntry(param)
+	return value
 }
 
-// GetSlice method get a slice entry of map
+// GetSlice method get a slice entry of map. A value that isn't a slice is recorded as a conversion error
+// on a Context, evaluating to an empty slice, and panics on the other maps
 func (c *TypedMap) GetSlice(param string) []interface{} {
-	return c.Get(param).([]interface{})
+	value := c.Get(param)
+	if slice, ok := value.([]interface{}); ok {
+		return slice
+	}
+	if value != nil && c.conversionFailed(param, "slice", value) {
+		return []interface{}{}
+	}
+	return value.([]interface{})
 }
 
-// GetString method get a string entry of map
+// GetString method get a string entry of map. A missing entry is an empty string.
 func (c *TypedMap) GetString(param string) string {
-	switch v := c.Get(param).(type) {
-	case bool:
-		return strconv.FormatBool(v)
-	default:
-		return fmt.Sprintf("%s", v)
+	value := c.Get(param)
+	if str, ok := toString(value); ok {
+		return str.(string)
 	}
+	return fmt.Sprintf("%v", value)
 }
 
-// GetInt method get a int entry of map
+// GetInt method get a int entry of map. A value that can't be converted is recorded as a conversion
+// error on a Context, evaluating to zero, and panics on the other maps
 func (c *TypedMap) GetInt(param string) int64 {
 	value := c.Get(param)
 
@@ -99,36 +115,55 @@ func (c *TypedMap) GetInt(param string) int64 {
 	switch v := value.(type) {
 	case float64:
 		return int64(v)
-	case string:
-		intValue, _ := strconv.Atoi(v)
-		return int64(intValue)
+	case string, json.Number:
+		intValue, ok := toInt64(v)
+		if !ok {
+			c.conversionFailed(param, "int", value)
+			return 0
+		}
+		return intValue.(int64)
 	case int:
 		return int64(v)
 	case int64:
 		return v
+	case Decimal:
+		return v.IntPart()
 	default:
+		if c.conversionFailed(param, "int", value) {
+			return 0
+		}
 		log.Panic("It's not possible to recover this parameter as int64")
 		panic("It's not possible to recover this parameter as int64")
 	}
 }
 
-// GetFloat method get a int entry of map
+// GetFloat method get a float entry of map. A value that can't be converted is recorded as a conversion
+// error on a Context, evaluating to zero, and panics on the other maps
 func (c *TypedMap) GetFloat(param string) float64 {
 	value := c.Get(param)
 	if value == nil {
 		return 0
 	}
 	switch v := value.(type) {
-	case string:
-		floatValue, _ := strconv.ParseFloat(v, 64)
-		return floatValue
+	case string, json.Number:
+		floatValue, ok := toFloat64(v)
+		if !ok {
+			c.conversionFailed(param, "float", value)
+			return 0
+		}
+		return floatValue.(float64)
 	case int:
 		return float64(v)
 	case int64:
 		return float64(v)
 	case float64:
 		return v
+	case Decimal:
+		return v.Float64()
 	default:
+		if c.conversionFailed(param, "float", value) {
+			return 0
+		}
 		log.Panic("fail to retrieve this param as float64")
 		panic("fail to retrieve this param as float64")
 	}
@@ -160,6 +195,9 @@ func (c *TypedMap) GetMap(param string) *TypedMap {
 			return tp
 		}
 
+		if c.conversionFailed(param, "map", value) {
+			return NewTypedMap()
+		}
 		panic("This param it's not a map")
 	}
 	return nil
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a step of a path on the entries of a map: a key of a map or, when index isn't
+// negative, an index of a slice.
+type pathSegment struct {
+	key   string
+	index int
+}
+
+// isPath checks if the param is a path on the nested entries, like `customer.address.state` or
+// `accounts[0].balance`.
+func isPath(param string) bool {
+	return strings.ContainsAny(param, ".[")
+}
+
+// parsePath splits a path on its segments. It returns false when the path is malformed.
+func parsePath(path string) ([]pathSegment, bool) {
+	segments := []pathSegment{}
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		indexes := ""
+		if open := strings.Index(part, "["); open >= 0 {
+			key = part[:open]
+			indexes = part[open:]
+		}
+
+		if key == "" && (len(segments) == 0 || indexes == "") {
+			return nil, false
+		}
+		if key != "" {
+			segments = append(segments, pathSegment{key: key, index: -1})
+		}
+
+		for indexes != "" {
+			end := strings.Index(indexes, "]")
+			if indexes[0] != '[' || end < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(indexes[1:end])
+			if err != nil || index < 0 {
+				return nil, false
+			}
+			segments = append(segments, pathSegment{index: index})
+			indexes = indexes[end+1:]
+		}
+	}
+	return segments, true
+}
+
+// lookupEntry retrieves a top-level entry of the map, with the `Getter` when it's set, so the remote
+// loaded params of a context can be the root of a path.
+func (c *TypedMap) lookupEntry(param string) interface{} {
+	if c.Getter != nil {
+		return c.Getter.GetEntry(param)
+	}
+	return c.GetEntry(param)
+}
+
+// lookupPath walks the path on the nested maps and slices of the entries, returning the value at its
+// end and whether it exists. A missing key, an index out of range or a step into a value that isn't a
+// map or a slice results in not found.
+func (c *TypedMap) lookupPath(path string) (interface{}, bool) {
+	segments, ok := parsePath(path)
+	if !ok {
+		return nil, false
+	}
+
+	value := c.lookupEntry(segments[0].key)
+	if value == nil {
+		_, exists := c.interfaceMap[segments[0].key]
+		return nil, exists && len(segments) == 1
+	}
+
+	for _, segment := range segments[1:] {
+		value, ok = stepPath(value, segment)
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// stepPath returns the entry of the segment on the value.
+func stepPath(value interface{}, segment pathSegment) (interface{}, bool) {
+	if segment.index >= 0 {
+		switch v := value.(type) {
+		case []interface{}:
+			if segment.index >= len(v) {
+				return nil, false
+			}
+			return v[segment.index], true
+		default:
+			rv := reflect.ValueOf(value)
+			if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || segment.index >= rv.Len() {
+				return nil, false
+			}
+			return rv.Index(segment.index).Interface(), true
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		entry, ok := v[segment.key]
+		return entry, ok
+	case *TypedMap:
+		if v.Has(segment.key) {
+			return v.Get(segment.key), true
+		}
+		return nil, false
+	default:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		entry := rv.MapIndex(reflect.ValueOf(segment.key).Convert(rv.Type().Key()))
+		if !entry.IsValid() {
+			return nil, false
+		}
+		return entry.Interface(), true
+	}
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+// pathEntries are the nested entries used on the path tests.
+func pathEntries() map[string]interface{} {
+	return map[string]interface{}{
+		"customer": map[string]interface{}{
+			"address": map[string]interface{}{"state": "DF", "number": "42"},
+			"vip":     true,
+		},
+		"accounts": []interface{}{
+			map[string]interface{}{"balance": 10.5, "tags": []interface{}{"savings"}},
+			map[string]interface{}{"balance": float64(300)},
+		},
+		"profile":   NewTypedMapFromMap(map[string]interface{}{"score": 700}),
+		"plain.key": "literal",
+	}
+}
+
+// TestParsePath checks if the paths are split on their segments and the malformed ones are rejected.
+func TestParsePath(t *testing.T) {
+	got, ok := parsePath("accounts[0].tags[1][2].name")
+	expected := []pathSegment{
+		{key: "accounts", index: -1},
+		{index: 0},
+		{key: "tags", index: -1},
+		{index: 1},
+		{index: 2},
+		{key: "name", index: -1},
+	}
+	if !ok || !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test Fail, we want %v, we got %v", expected, got)
+	}
+
+	for _, path := range []string{"[0]", "a..b", "a.", "a[x]", "a[-1]", "a[0", "a[0]b"} {
+		if _, ok := parsePath(path); ok {
+			t.Errorf("the path %s must be malformed", path)
+		}
+	}
+}
+
+// TestGetPath checks if the typed getters access the nested maps and slices by path.
+func TestGetPath(t *testing.T) {
+	tm := NewTypedMapFromMap(pathEntries())
+
+	if got := tm.GetString("customer.address.state"); got != "DF" {
+		t.Errorf("Test Fail, we want %v, we got %v", "DF", got)
+	}
+	if got := tm.GetInt("customer.address.number"); got != 42 {
+		t.Errorf("Test Fail, we want %v, we got %v", 42, got)
+	}
+	if got := tm.GetBool("customer.vip"); got != true {
+		t.Errorf("Test Fail, we want %v, we got %v", true, got)
+	}
+	if got := tm.GetFloat("accounts[0].balance"); got != 10.5 {
+		t.Errorf("Test Fail, we want %v, we got %v", 10.5, got)
+	}
+	if got := tm.GetInt("accounts[1].balance"); got != 300 {
+		t.Errorf("Test Fail, we want %v, we got %v", 300, got)
+	}
+	if got := tm.GetString("accounts[0].tags[0]"); got != "savings" {
+		t.Errorf("Test Fail, we want %v, we got %v", "savings", got)
+	}
+	if got := tm.GetInt("profile.score"); got != 700 {
+		t.Errorf("Test Fail, we want %v, we got %v", 700, got)
+	}
+	if got := tm.GetMap("customer.address").GetString("state"); got != "DF" {
+		t.Errorf("Test Fail, we want %v, we got %v", "DF", got)
+	}
+	if got := tm.GetString("plain.key"); got != "literal" {
+		t.Errorf("Test Fail, we want %v, we got %v", "literal", got)
+	}
+
+	for _, path := range []string{"customer.phone", "accounts[2].balance", "customer.vip.level", "accounts.balance"} {
+		if got := tm.Get(path); got != nil {
+			t.Errorf("Test Fail, we want %v, we got %v", nil, got)
+		}
+	}
+	if got := tm.GetInt("accounts[5].balance"); got != 0 {
+		t.Errorf("Test Fail, we want %v, we got %v", 0, got)
+	}
+}
+
+// TestHasPath checks if Has finds the nested entries by path.
+func TestHasPath(t *testing.T) {
+	tm := NewTypedMapFromMap(pathEntries())
+
+	for _, path := range []string{"customer.address.state", "accounts[1]", "accounts[0].tags[0]", "profile.score", "plain.key"} {
+		if !tm.Has(path) {
+			t.Errorf("the path %s must exist", path)
+		}
+	}
+
+	for _, path := range []string{"customer.address.city", "accounts[2]", "customer.vip.level", "missing.key", "a..b"} {
+		if tm.Has(path) {
+			t.Errorf("the path %s must not exist", path)
+		}
+	}
+}
+
+// TestGetPathRemoteLoadedRoot checks if a remote loaded param is loaded when it's the root of a path.
+func TestGetPathRemoteLoadedRoot(t *testing.T) {
+	RegisterResolver("customerresolver", ConstantResolver{
+		"customer": map[string]interface{}{
+			"address":  map[string]interface{}{"state": "SP"},
+			"accounts": []interface{}{map[string]interface{}{"balance": 25}},
+		},
+	})
+	defer UnregisterResolver("customerresolver")
+
+	ctx := NewContext()
+	ctx.RegistryRemoteLoaded("customer", "customerresolver")
+
+	if !ctx.Has("customer.address.state") {
+		t.Error("the path customer.address.state must exist")
+	}
+	if got := ctx.GetString("customer.address.state"); got != "SP" {
+		t.Errorf("Test Fail, we want %v, we got %v", "SP", got)
+	}
+	if got := ctx.GetInt("customer.accounts[0].balance"); got != 25 {
+		t.Errorf("Test Fail, we want %v, we got %v", 25, got)
+	}
+}